@@ -0,0 +1,9 @@
+// Package assets provides the files of the assets directory, embedded into the binary.
+package assets
+
+import "embed"
+
+// FS contains the embedded asset files, addressed by their name relative to the assets directory.
+//
+//go:embed *.png
+var FS embed.FS
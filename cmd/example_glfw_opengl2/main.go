@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"os"
 
@@ -12,23 +14,39 @@ import (
 )
 
 func main() {
-	context := imgui.CreateContext()
-	defer context.Destroy()
+	err := run()
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(-1)
+	}
+}
+
+// run returns the error of the program. The program only exits after run returned,
+// so that all resources are released by their deferred calls.
+func run() error {
+	textureDir := flag.String("textures", "", "load the textures from this directory, and reload them when they change")
+	flag.Parse()
+	options := []example.Option{example.WithViewports(), example.WithDocking(), example.WithWindowState()}
+	if *textureDir != "" {
+		options = append(options, example.WithTextureDir(*textureDir))
+	}
+
+	imguiContext := imgui.CreateContext()
+	defer imguiContext.Destroy()
 	io := imgui.CurrentIO()
 
 	platform, err := platforms.NewGLFW(io, platforms.GLFWClientAPIOpenGL2)
 	if err != nil {
-		_, _ = fmt.Fprintf(os.Stderr, "%v\n", err)
-		os.Exit(-1)
+		return err
 	}
-	defer platform.Dispose()
 
 	renderer, err := renderers.NewOpenGL2(io)
 	if err != nil {
-		_, _ = fmt.Fprintf(os.Stderr, "%v\n", err)
-		os.Exit(-1)
+		platform.Dispose()
+		return err
 	}
-	defer renderer.Dispose()
+	platform.SetWindowRenderer(renderer)
 
-	example.Run(platform, renderer)
+	// Run disposes the renderer and the platform.
+	return example.Run(context.Background(), platform, renderer, options...)
 }
@@ -1,34 +1,52 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"os"
 
-	cimgui "github.com/AllenDang/cimgui-go"
+	imgui "github.com/AllenDang/cimgui-go"
+
 	"github.com/ptxmac/cimgui-go-examples/internal/example"
 	"github.com/ptxmac/cimgui-go-examples/internal/platforms"
 	"github.com/ptxmac/cimgui-go-examples/internal/renderers"
 )
 
 func main() {
-	context := cimgui.CreateContext()
+	err := run()
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(-1)
+	}
+}
 
-	defer context.Destroy()
-	io := cimgui.CurrentIO()
+// run returns the error of the program. The program only exits after run returned,
+// so that all resources are released by their deferred calls.
+func run() error {
+	textureDir := flag.String("textures", "", "load the textures from this directory, and reload them when they change")
+	flag.Parse()
+	options := []example.Option{example.WithViewports(), example.WithDocking(), example.WithWindowState()}
+	if *textureDir != "" {
+		options = append(options, example.WithTextureDir(*textureDir))
+	}
+
+	imguiContext := imgui.CreateContext()
+	defer imguiContext.Destroy()
+	io := imgui.CurrentIO()
 
 	platform, err := platforms.NewGLFW(io, platforms.GLFWClientAPIOpenGL3)
 	if err != nil {
-		_, _ = fmt.Fprintf(os.Stderr, "%v\n", err)
-		os.Exit(-1)
+		return err
 	}
-	defer platform.Dispose()
 
 	renderer, err := renderers.NewOpenGL3(io)
 	if err != nil {
-		_, _ = fmt.Fprintf(os.Stderr, "%v\n", err)
-		os.Exit(-1)
+		platform.Dispose()
+		return err
 	}
-	defer renderer.Dispose()
+	platform.SetWindowRenderer(renderer)
 
-	example.Run(platform, renderer)
+	// Run disposes the renderer and the platform.
+	return example.Run(context.Background(), platform, renderer, options...)
 }
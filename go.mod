@@ -4,6 +4,7 @@ require (
 	github.com/AllenDang/cimgui-go v0.0.0-20230502145512-97518c13c52b
 	github.com/go-gl/glfw v0.0.0-20221017161538-93cebf72946b
 	github.com/go-gl/glfw/v3.3/glfw v0.0.0-20221017161538-93cebf72946b
+	golang.org/x/image v0.18.0
 )
 
 go 1.19
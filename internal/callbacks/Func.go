@@ -0,0 +1,78 @@
+package callbacks
+
+// #include "callbacks.h"
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/AllenDang/cimgui-go"
+)
+
+// Func draws custom content in place of a command of a draw list. It receives the data it was added with.
+// The renderer always calls it, with the context of the rendered viewport being current. If the clip rectangle
+// of the command is visible, it is applied as scissor box; otherwise the scissor box is left as it is, and the
+// function should check command.ClipRect() before drawing. The renderer sets up its own state again afterwards,
+// so the function may change any state without restoring it.
+type Func func(list imgui.DrawList, command imgui.DrawCmd, data any)
+
+type entry struct {
+	draw  Func
+	data  any
+	frame int
+}
+
+// entries holds the functions of the draw lists of the current and the previous frame.
+// Draw lists are only built and rendered on the thread of the frame loop, so no lock is needed.
+var (
+	entries     = make(map[uintptr]entry)
+	nextID      uintptr
+	prunedFrame int
+)
+
+// Add appends a command to the draw list that calls the function with the given data when it is rendered.
+// The function is only kept for the frame it was added in, so it must be added anew with every frame.
+// Add must only be called from the thread of the frame loop, between imgui.NewFrame() and imgui.Render().
+func Add(list imgui.DrawList, draw Func, data any) {
+	frame := imgui.FrameCount()
+	prune(frame)
+	nextID++
+	entries[nextID] = entry{draw: draw, data: data, frame: frame}
+	C.addDrawCallback(drawList(list), C.uintptr_t(nextID))
+}
+
+// AddResetRenderState appends a command to the draw list that makes the renderer set up its render state again.
+// This is needed after commands that change the state without being rendered by the renderer itself.
+func AddResetRenderState(list imgui.DrawList) {
+	C.addResetRenderState(drawList(list))
+}
+
+// IsResetRenderState returns true if the command was added with AddResetRenderState.
+// Renderers check for it before they call a user callback, as the command has no function to call.
+func IsResetRenderState(command imgui.DrawCmd) bool {
+	return bool(C.isResetRenderState((*C.ImDrawCmd)(unsafe.Pointer(command))))
+}
+
+// prune removes the functions of frames before the previous one, once per frame.
+func prune(frame int) {
+	if frame == prunedFrame {
+		return
+	}
+	prunedFrame = frame
+	for id, entry := range entries {
+		if entry.frame < frame-1 {
+			delete(entries, id)
+		}
+	}
+}
+
+func drawList(list imgui.DrawList) *C.ImDrawList {
+	return (*C.ImDrawList)(unsafe.Pointer(list))
+}
+
+//export callbacksDraw
+func callbacksDraw(list *C.ImDrawList, command *C.ImDrawCmd, id C.uintptr_t) {
+	if entry, known := entries[uintptr(id)]; known {
+		entry.draw(imgui.DrawList(unsafe.Pointer(list)), imgui.DrawCmd(unsafe.Pointer(command)), entry.data)
+	}
+}
@@ -0,0 +1,21 @@
+#include "callbacks.h"
+#include "_cgo_export.h"
+
+// resetRenderState is the value of ImDrawCallback_ResetRenderState of imgui.h.
+#define resetRenderState ((ImDrawCallback)(-1))
+
+static void drawCallback(const ImDrawList *list, const ImDrawCmd *cmd) {
+	callbacksDraw((ImDrawList *)list, (ImDrawCmd *)cmd, (uintptr_t)cmd->UserCallbackData);
+}
+
+void addDrawCallback(ImDrawList *list, uintptr_t id) {
+	ImDrawList_AddCallback(list, drawCallback, (void *)id);
+}
+
+void addResetRenderState(ImDrawList *list) {
+	ImDrawList_AddCallback(list, resetRenderState, 0);
+}
+
+bool isResetRenderState(const ImDrawCmd *cmd) {
+	return cmd->UserCallback == resetRenderState;
+}
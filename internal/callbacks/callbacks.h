@@ -0,0 +1,35 @@
+// Declarations of the imgui types that are needed to add draw callbacks and to recognize them.
+// They mirror the layout of cimgui.h of the imgui version used by github.com/AllenDang/cimgui-go,
+// which does not provide access to the callbacks of ImDrawCmd itself.
+#ifndef CALLBACKS_CALLBACKS_H
+#define CALLBACKS_CALLBACKS_H
+
+#include <stdbool.h>
+#include <stdint.h>
+
+typedef struct ImVec4 { float x, y, z, w; } ImVec4;
+typedef struct ImDrawList ImDrawList;
+typedef struct ImDrawCmd ImDrawCmd;
+typedef void (*ImDrawCallback)(const ImDrawList *parent_list, const ImDrawCmd *cmd);
+
+struct ImDrawCmd {
+	ImVec4 ClipRect;
+	void *TextureId;
+	unsigned int VtxOffset;
+	unsigned int IdxOffset;
+	unsigned int ElemCount;
+	ImDrawCallback UserCallback;
+	void *UserCallbackData;
+};
+
+// ImDrawList_AddCallback is provided by cimgui.
+extern void ImDrawList_AddCallback(ImDrawList *self, ImDrawCallback callback, void *callback_data);
+
+// addDrawCallback adds a command that calls the Go function registered with the given identifier.
+void addDrawCallback(ImDrawList *list, uintptr_t id);
+// addResetRenderState adds a command that asks the renderer to set up its render state again.
+void addResetRenderState(ImDrawList *list);
+// isResetRenderState returns true if the command asks the renderer to set up its render state again.
+bool isResetRenderState(const ImDrawCmd *cmd);
+
+#endif
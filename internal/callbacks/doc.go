@@ -0,0 +1,4 @@
+// Package callbacks adds draw callbacks with Go functions to imgui draw lists.
+// A callback draws custom content, such as an OpenGL scene, at its position among the commands of a
+// draw list. The renderers call it while they render the draw data, and set up their own state again afterwards.
+package callbacks
@@ -0,0 +1,14 @@
+package dialogs
+
+// Result is the answer of the user to a dialog.
+type Result struct {
+	// OK is true if the user confirmed the dialog. It is false if the user canceled the dialog,
+	// or the context of the dialog ended before the user answered.
+	OK bool
+	// Text is the entered text of a prompt.
+	Text string
+	// Choice is the index of the chosen item of a choice dialog, or -1.
+	Choice int
+}
+
+var canceled = Result{Choice: -1}
@@ -0,0 +1,228 @@
+package dialogs
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/AllenDang/cimgui-go"
+
+	"github.com/ptxmac/cimgui-go-examples/internal/dispatch"
+)
+
+// wrapWidth is the width of the messages, in multiples of the font size.
+const wrapWidth = 30
+
+// choiceRows is the number of rows of the list of a choice dialog.
+const choiceRows = 8
+
+type kind int
+
+const (
+	kindConfirm kind = iota
+	kindPrompt
+	kindChoose
+	kindError
+)
+
+var titles = map[kind]string{
+	kindConfirm: "Confirm",
+	kindPrompt:  "Input",
+	kindChoose:  "Choose",
+	kindError:   "Error",
+}
+
+var errorColor = imgui.Vec4{X: 1, Y: 0.4, Z: 0.4, W: 1}
+
+type dialog struct {
+	id      string
+	kind    kind
+	ctx     context.Context
+	message string
+	text    string
+	choices []string
+	choice  int
+	result  chan Result
+}
+
+// Service queues dialogs and shows them one at a time.
+// The methods that ask for an answer may be called from any goroutine; Frame and Close
+// must only be called from the thread that runs the frame loop.
+type Service struct {
+	dispatcher *dispatch.Dispatcher
+
+	mutex  sync.Mutex
+	queue  []*dialog
+	nextID int
+	closed bool
+
+	current *dialog
+}
+
+// NewService returns a service that wakes the frame loop with given dispatcher when a dialog is queued.
+func NewService(dispatcher *dispatch.Dispatcher) *Service {
+	return &Service{dispatcher: dispatcher}
+}
+
+// Confirm asks the user to confirm the message. Result.OK tells the answer.
+// The channel receives a single result and is closed afterwards.
+// If the context ends before the user answers, the dialog is removed and the result is canceled.
+func (service *Service) Confirm(ctx context.Context, message string) <-chan Result {
+	return service.enqueue(&dialog{kind: kindConfirm, ctx: ctx, message: message})
+}
+
+// Prompt asks the user to enter a text, starting with the initial one. Result.Text is the entered text.
+func (service *Service) Prompt(ctx context.Context, message, initial string) <-chan Result {
+	return service.enqueue(&dialog{kind: kindPrompt, ctx: ctx, message: message, text: initial})
+}
+
+// Choose asks the user to choose one of the items. Result.Choice is the index of the chosen item.
+func (service *Service) Choose(ctx context.Context, message string, choices []string) <-chan Result {
+	choices = append([]string(nil), choices...)
+	return service.enqueue(&dialog{kind: kindChoose, ctx: ctx, message: message, choices: choices, choice: -1})
+}
+
+// Error shows the error until the user acknowledges it.
+func (service *Service) Error(ctx context.Context, err error) <-chan Result {
+	return service.enqueue(&dialog{kind: kindError, ctx: ctx, message: err.Error()})
+}
+
+func (service *Service) enqueue(d *dialog) <-chan Result {
+	d.result = make(chan Result, 1)
+	service.mutex.Lock()
+	if service.closed {
+		service.mutex.Unlock()
+		d.answer(canceled)
+		return d.result
+	}
+	service.nextID++
+	d.id = fmt.Sprintf("%s##dialog%d", titles[d.kind], service.nextID)
+	service.queue = append(service.queue, d)
+	service.mutex.Unlock()
+	service.dispatcher.Wake()
+	return d.result
+}
+
+// next removes the first dialog from the queue whose context has not ended yet.
+func (service *Service) next() *dialog {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+	for len(service.queue) > 0 {
+		d := service.queue[0]
+		service.queue = service.queue[1:]
+		if d.ctx.Err() == nil {
+			return d
+		}
+		d.answer(canceled)
+	}
+	return nil
+}
+
+// Frame shows the current dialog. It must be called between imgui.NewFrame() and imgui.Render(),
+// outside of any window. The next dialog is opened once no other popup is open.
+func (service *Service) Frame() {
+	d := service.current
+	if d == nil {
+		if imgui.IsPopupOpenStrV("", imgui.PopupFlagsAnyPopup) {
+			return
+		}
+		d = service.next()
+		if d == nil {
+			return
+		}
+		service.current = d
+		imgui.OpenPopupStr(d.id)
+	}
+	if !imgui.BeginPopupModalV(d.id, nil, imgui.WindowFlagsAlwaysAutoResize) {
+		// The popup was closed by imgui itself, for example because another popup replaced it.
+		service.current = nil
+		d.answer(canceled)
+		return
+	}
+	result, answered := d.build()
+	if !answered && (d.ctx.Err() != nil) {
+		result, answered = canceled, true
+	}
+	if answered {
+		imgui.CloseCurrentPopup()
+		service.current = nil
+		d.answer(result)
+	}
+	imgui.EndPopup()
+}
+
+// Close cancels the current and all queued dialogs. Dialogs asked for afterwards are canceled immediately.
+func (service *Service) Close() {
+	service.mutex.Lock()
+	service.closed = true
+	queue := service.queue
+	service.queue = nil
+	service.mutex.Unlock()
+	if service.current != nil {
+		queue = append(queue, service.current)
+		service.current = nil
+	}
+	for _, d := range queue {
+		d.answer(canceled)
+	}
+}
+
+func (d *dialog) answer(result Result) {
+	d.result <- result
+	close(d.result)
+}
+
+// build adds the content of the dialog to the popup. It returns true once the user answered.
+func (d *dialog) build() (Result, bool) {
+	imgui.PushTextWrapPosV(imgui.FontSize() * wrapWidth)
+	if d.kind == kindError {
+		imgui.PushStyleColorVec4(imgui.ColText, errorColor)
+		imgui.TextUnformatted(d.message)
+		imgui.PopStyleColor()
+	} else {
+		imgui.TextUnformatted(d.message)
+	}
+	imgui.PopTextWrapPos()
+
+	confirmed := false
+	switch d.kind {
+	case kindPrompt:
+		if imgui.IsWindowAppearing() {
+			imgui.SetKeyboardFocusHere()
+		}
+		imgui.PushItemWidth(imgui.FontSize() * wrapWidth)
+		confirmed = imgui.InputTextWithHint("##text", "", &d.text, imgui.InputTextFlagsEnterReturnsTrue, nil)
+		imgui.PopItemWidth()
+	case kindChoose:
+		size := imgui.Vec2{X: imgui.FontSize() * wrapWidth, Y: imgui.TextLineHeightWithSpacing() * choiceRows}
+		if imgui.BeginListBoxV("##choices", size) {
+			for i, choice := range d.choices {
+				imgui.PushIDInt(int32(i))
+				if imgui.SelectableBoolV(choice, i == d.choice, imgui.SelectableFlagsAllowDoubleClick, imgui.Vec2{}) {
+					d.choice = i
+					confirmed = imgui.IsMouseDoubleClicked(imgui.MouseButtonLeft)
+				}
+				imgui.PopID()
+			}
+			imgui.EndListBox()
+		}
+	}
+
+	imgui.BeginDisabledV((d.kind == kindChoose) && (d.choice < 0))
+	confirmed = imgui.Button("OK") || confirmed
+	imgui.EndDisabled()
+	if d.kind == kindConfirm || d.kind == kindError {
+		imgui.SetItemDefaultFocus()
+	}
+	if confirmed {
+		return Result{OK: true, Text: d.text, Choice: d.choice}, true
+	}
+	if d.kind == kindError {
+		return canceled, imgui.IsKeyPressedBool(imgui.KeyEscape)
+	}
+	imgui.SameLine()
+	if imgui.Button("Cancel") || imgui.IsKeyPressedBool(imgui.KeyEscape) {
+		return canceled, true
+	}
+	return Result{}, false
+}
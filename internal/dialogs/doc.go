@@ -0,0 +1,5 @@
+// Package dialogs shows modal dialogs on behalf of any code, including goroutines.
+// Callers ask for a confirmation, a text, a choice, or acknowledge an error, and receive the
+// answer of the user on a channel. The dialogs are queued and shown one after another as
+// imgui popup modals, which spares the callers the state of OpenPopup and BeginPopupModal.
+package dialogs
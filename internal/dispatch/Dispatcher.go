@@ -0,0 +1,118 @@
+package dispatch
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Dispatcher queues tasks from any goroutine and runs them on the thread of the frame loop.
+// Post, PostAndWait and Wake may be called from any goroutine; RunPending, Wait and Close
+// must only be called from the thread that runs the frame loop.
+type Dispatcher struct {
+	mutex  sync.Mutex
+	tasks  []func()
+	closed bool
+
+	// wake holds at most one signal, which ends the current or the next Wait.
+	wake chan struct{}
+	// done is closed by Close, releasing the goroutines in PostAndWait.
+	done chan struct{}
+}
+
+// NewDispatcher returns an empty dispatcher.
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{
+		wake: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+}
+
+// Post queues the task to be run before the next frame and wakes the frame loop.
+// Post never blocks, so it may also be called from tasks and from the frame loop itself.
+// It returns false if the dispatcher is closed; the task is dropped in that case.
+func (dispatcher *Dispatcher) Post(task func()) bool {
+	dispatcher.mutex.Lock()
+	if dispatcher.closed {
+		dispatcher.mutex.Unlock()
+		return false
+	}
+	dispatcher.tasks = append(dispatcher.tasks, task)
+	dispatcher.mutex.Unlock()
+	dispatcher.Wake()
+	return true
+}
+
+// PostAndWait queues the task and blocks until it has run. It returns ErrClosed if the dispatcher
+// is closed before the task ran, and the error of the context if the context ends before that.
+// A task whose wait was canceled still runs. PostAndWait must not be called from the frame loop,
+// which would wait for itself.
+func (dispatcher *Dispatcher) PostAndWait(ctx context.Context, task func()) error {
+	ran := make(chan struct{})
+	if !dispatcher.Post(func() {
+		defer close(ran)
+		task()
+	}) {
+		return ErrClosed
+	}
+	select {
+	case <-ran:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-dispatcher.done:
+		// Close is called from the frame loop, so the task either ran before, or never runs.
+		select {
+		case <-ran:
+			return nil
+		default:
+			return ErrClosed
+		}
+	}
+}
+
+// Wake ends the current or the next Wait of the frame loop, so that the next frame is started early.
+// Goroutines call it after they changed state that the user interface shows.
+func (dispatcher *Dispatcher) Wake() {
+	select {
+	case dispatcher.wake <- struct{}{}:
+	default: // A signal is pending already
+	}
+}
+
+// RunPending runs the tasks that are queued, in the order they were posted.
+// Tasks posted while running are kept for the next call, so that a task posting itself
+// does not prevent the frame loop from continuing.
+func (dispatcher *Dispatcher) RunPending() {
+	dispatcher.mutex.Lock()
+	tasks := dispatcher.tasks
+	dispatcher.tasks = nil
+	dispatcher.mutex.Unlock()
+	for _, task := range tasks {
+		task()
+	}
+}
+
+// Wait blocks until the timeout elapsed, the context ended, or the dispatcher is woken by Wake or Post.
+// A signal that was given since the previous Wait ends it immediately.
+func (dispatcher *Dispatcher) Wait(ctx context.Context, timeout time.Duration) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-dispatcher.wake:
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
+
+// Close drops the queued tasks and rejects further ones. Goroutines waiting in PostAndWait return ErrClosed.
+func (dispatcher *Dispatcher) Close() {
+	dispatcher.mutex.Lock()
+	defer dispatcher.mutex.Unlock()
+	if dispatcher.closed {
+		return
+	}
+	dispatcher.closed = true
+	dispatcher.tasks = nil
+	close(dispatcher.done)
+}
@@ -0,0 +1,197 @@
+package dispatch_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ptxmac/cimgui-go-examples/internal/dispatch"
+)
+
+// frameLoop drains the dispatcher like the frame loop does, until stop is closed.
+// It returns a channel that is closed once the loop has ended.
+func frameLoop(dispatcher *dispatch.Dispatcher, stop <-chan struct{}) <-chan struct{} {
+	ended := make(chan struct{})
+	go func() {
+		defer close(ended)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			dispatcher.RunPending()
+			dispatcher.Wait(context.Background(), time.Millisecond)
+		}
+	}()
+	return ended
+}
+
+func TestDispatcherRunsTasksOfConcurrentProducers(t *testing.T) {
+	const producers = 32
+	const tasksPerProducer = 100
+
+	dispatcher := dispatch.NewDispatcher()
+	stop := make(chan struct{})
+	ended := frameLoop(dispatcher, stop)
+
+	// The counter is only touched by the tasks; the race detector reports it if they ran concurrently.
+	counter := 0
+	var failures int32
+	var producersDone sync.WaitGroup
+	for producer := 0; producer < producers; producer++ {
+		producersDone.Add(1)
+		go func(producer int) {
+			defer producersDone.Done()
+			for i := 0; i < tasksPerProducer; i++ {
+				if (producer % 2) == 0 {
+					if !dispatcher.Post(func() { counter++ }) {
+						atomic.AddInt32(&failures, 1)
+					}
+				} else if err := dispatcher.PostAndWait(context.Background(), func() { counter++ }); err != nil {
+					atomic.AddInt32(&failures, 1)
+				}
+			}
+		}(producer)
+	}
+	producersDone.Wait()
+	// The posted tasks of the last producers may still be queued; a final task reports once all ran.
+	var result int
+	err := dispatcher.PostAndWait(context.Background(), func() { result = counter })
+	close(stop)
+	<-ended
+	dispatcher.Close()
+
+	if err != nil {
+		t.Fatalf("final PostAndWait failed: %v", err)
+	}
+	if failures != 0 {
+		t.Errorf("%d posts failed", failures)
+	}
+	if result != producers*tasksPerProducer {
+		t.Errorf("counter is %d, expected %d", result, producers*tasksPerProducer)
+	}
+}
+
+func TestDispatcherRunsTasksInOrder(t *testing.T) {
+	dispatcher := dispatch.NewDispatcher()
+	defer dispatcher.Close()
+
+	var order []int
+	for i := 0; i < 3; i++ {
+		value := i
+		dispatcher.Post(func() { order = append(order, value) })
+	}
+	dispatcher.RunPending()
+
+	if len(order) != 3 || order[0] != 0 || order[1] != 1 || order[2] != 2 {
+		t.Errorf("tasks ran in order %v", order)
+	}
+}
+
+func TestDispatcherKeepsTasksPostedWhileRunningForNextCall(t *testing.T) {
+	dispatcher := dispatch.NewDispatcher()
+	defer dispatcher.Close()
+
+	runs := 0
+	var task func()
+	task = func() {
+		runs++
+		dispatcher.Post(task)
+	}
+	dispatcher.Post(task)
+	dispatcher.RunPending()
+	dispatcher.RunPending()
+
+	if runs != 2 {
+		t.Errorf("task ran %d times, expected 2", runs)
+	}
+}
+
+func TestDispatcherPostAndWaitReturnsErrClosedOnClose(t *testing.T) {
+	dispatcher := dispatch.NewDispatcher()
+	result := make(chan error)
+	go func() {
+		result <- dispatcher.PostAndWait(context.Background(), func() {
+			t.Error("task ran after close")
+		})
+	}()
+	waitForQueuedTask(t, dispatcher)
+	dispatcher.Close()
+
+	select {
+	case err := <-result:
+		if !errors.Is(err, dispatch.ErrClosed) {
+			t.Errorf("PostAndWait returned %v, expected ErrClosed", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("PostAndWait did not return after close")
+	}
+	dispatcher.RunPending() // Dropped tasks must not run
+}
+
+func TestDispatcherRejectsTasksAfterClose(t *testing.T) {
+	dispatcher := dispatch.NewDispatcher()
+	dispatcher.Close()
+	dispatcher.Close() // Closing twice is allowed
+
+	if dispatcher.Post(func() {}) {
+		t.Error("Post accepted a task after close")
+	}
+	if err := dispatcher.PostAndWait(context.Background(), func() {}); !errors.Is(err, dispatch.ErrClosed) {
+		t.Errorf("PostAndWait returned %v, expected ErrClosed", err)
+	}
+}
+
+func TestDispatcherPostAndWaitReturnsErrorOfCanceledContext(t *testing.T) {
+	dispatcher := dispatch.NewDispatcher()
+	defer dispatcher.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error)
+	ran := false
+	go func() {
+		result <- dispatcher.PostAndWait(ctx, func() { ran = true })
+	}()
+	waitForQueuedTask(t, dispatcher)
+	cancel()
+
+	select {
+	case err := <-result:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("PostAndWait returned %v, expected context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("PostAndWait did not return after cancel")
+	}
+	// A task whose wait was canceled still runs.
+	dispatcher.RunPending()
+	if !ran {
+		t.Error("task of canceled wait did not run")
+	}
+}
+
+func TestDispatcherWaitEndsOnWake(t *testing.T) {
+	dispatcher := dispatch.NewDispatcher()
+	defer dispatcher.Close()
+	dispatcher.Wake()
+
+	start := time.Now()
+	dispatcher.Wait(context.Background(), time.Minute)
+	if time.Since(start) > time.Second {
+		t.Error("Wait did not end on a pending wake signal")
+	}
+}
+
+// waitForQueuedTask blocks until a task was posted, which is signaled by the wake channel.
+func waitForQueuedTask(t *testing.T, dispatcher *dispatch.Dispatcher) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	dispatcher.Wait(ctx, time.Second)
+	if ctx.Err() != nil {
+		t.Fatal("no task was posted")
+	}
+}
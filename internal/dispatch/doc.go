@@ -0,0 +1,5 @@
+// Package dispatch runs functions on the thread of the frame loop.
+// The imgui and OpenGL functions must only be called from the thread that created the context,
+// so goroutines that fetch data in the background post their updates of the user interface
+// to a Dispatcher, which runs them between two frames.
+package dispatch
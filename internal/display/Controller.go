@@ -0,0 +1,13 @@
+package display
+
+// Controller is implemented by platforms that can change the display mode of their main window.
+type Controller interface {
+	// Monitors returns the connected monitors, with the primary monitor first.
+	Monitors() []Monitor
+	// DisplayMode returns the current mode, and the name of the monitor the window is on.
+	DisplayMode() (Mode, string)
+	// SetDisplayMode switches to the mode on the named monitor. The video mode only applies to ModeFullscreen;
+	// its zero value keeps the current video mode of the monitor. Switching to ModeWindowed restores the
+	// geometry the window had before it left the windowed mode.
+	SetDisplayMode(mode Mode, monitor string, video VideoMode) error
+}
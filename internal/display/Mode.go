@@ -0,0 +1,28 @@
+package display
+
+// Mode describes how the main window covers the display.
+type Mode int
+
+// This is a list of Mode constants.
+const (
+	// ModeWindowed shows a regular, decorated window.
+	ModeWindowed Mode = iota
+	// ModeBorderless shows an undecorated window that covers the whole monitor, without changing its video mode.
+	ModeBorderless
+	// ModeFullscreen takes exclusive control of the monitor, optionally with a different video mode.
+	ModeFullscreen
+)
+
+// String returns a human readable name of the mode.
+func (mode Mode) String() string {
+	switch mode {
+	case ModeWindowed:
+		return "Windowed"
+	case ModeBorderless:
+		return "Borderless fullscreen"
+	case ModeFullscreen:
+		return "Exclusive fullscreen"
+	default:
+		return "Unknown"
+	}
+}
@@ -0,0 +1,30 @@
+package display
+
+import "fmt"
+
+// VideoMode describes a resolution and refresh rate of a monitor.
+// The zero value stands for the current video mode of a monitor.
+type VideoMode struct {
+	Width       int
+	Height      int
+	RefreshRate int
+}
+
+// String returns the video mode in the common notation, such as "1920x1080 @ 60 Hz".
+func (mode VideoMode) String() string {
+	return fmt.Sprintf("%dx%d @ %d Hz", mode.Width, mode.Height, mode.RefreshRate)
+}
+
+// Monitor describes a connected monitor.
+type Monitor struct {
+	// Name identifies the monitor. Names are not necessarily unique among identical models.
+	Name    string
+	Primary bool
+	// X and Y are the position of the monitor on the virtual desktop, in screen coordinates.
+	X int
+	Y int
+	// Current is the video mode the monitor currently runs with.
+	Current VideoMode
+	// Modes lists the supported video modes, in ascending order.
+	Modes []VideoMode
+}
@@ -0,0 +1,121 @@
+package display
+
+import (
+	"fmt"
+
+	"github.com/AllenDang/cimgui-go"
+)
+
+// Switcher offers the display modes of a controller in a menu, and toggles fullscreen with a hotkey.
+// A Switcher must only be used from the thread that runs the frame loop.
+type Switcher struct {
+	controller Controller
+	hotkey     imgui.Key
+
+	// fullscreenMode, fullscreenMonitor and fullscreenVideo are the last fullscreen mode chosen, used by Toggle.
+	fullscreenMode    Mode
+	fullscreenMonitor string
+	fullscreenVideo   VideoMode
+
+	err error
+}
+
+// NewSwitcher returns a switcher for given controller. The hotkey toggles fullscreen, imgui.KeyF11 is common.
+func NewSwitcher(controller Controller, hotkey imgui.Key) *Switcher {
+	return &Switcher{
+		controller:     controller,
+		hotkey:         hotkey,
+		fullscreenMode: ModeBorderless,
+	}
+}
+
+// Err returns the error of the last switch, or nil.
+func (switcher *Switcher) Err() error {
+	return switcher.err
+}
+
+// Toggle switches between windowed mode and the last chosen fullscreen mode.
+// Without a previous choice, the window becomes borderless fullscreen on its current monitor.
+func (switcher *Switcher) Toggle() {
+	mode, monitor := switcher.controller.DisplayMode()
+	if mode != ModeWindowed {
+		switcher.set(ModeWindowed, monitor, VideoMode{})
+		return
+	}
+	if switcher.fullscreenMonitor != "" {
+		monitor = switcher.fullscreenMonitor
+	}
+	switcher.set(switcher.fullscreenMode, monitor, switcher.fullscreenVideo)
+}
+
+// HandleHotkey toggles fullscreen if the hotkey was pressed. It must be called between imgui.NewFrame() and imgui.Render().
+func (switcher *Switcher) HandleHotkey() {
+	if imgui.IsKeyPressedBoolV(switcher.hotkey, false) {
+		switcher.Toggle()
+	}
+}
+
+// Menu adds the "Display" menu. It must be called within a menu bar.
+func (switcher *Switcher) Menu() {
+	if !imgui.BeginMenu("Display") {
+		return
+	}
+	current, currentMonitor := switcher.controller.DisplayMode()
+	if imgui.MenuItemBoolV("Windowed", "", current == ModeWindowed, true) {
+		switcher.set(ModeWindowed, currentMonitor, VideoMode{})
+	}
+	if imgui.MenuItemBoolV("Toggle fullscreen", imgui.KeyName(switcher.hotkey), false, true) {
+		switcher.Toggle()
+	}
+	imgui.Separator()
+
+	monitors := switcher.controller.Monitors()
+	if imgui.BeginMenuV(ModeBorderless.String(), len(monitors) > 0) {
+		for _, monitor := range monitors {
+			selected := (current == ModeBorderless) && (monitor.Name == currentMonitor)
+			if imgui.MenuItemBoolV(monitorLabel(monitor), "", selected, true) {
+				switcher.set(ModeBorderless, monitor.Name, VideoMode{})
+			}
+		}
+		imgui.EndMenu()
+	}
+	if imgui.BeginMenuV(ModeFullscreen.String(), len(monitors) > 0) {
+		for _, monitor := range monitors {
+			if !imgui.BeginMenu(monitorLabel(monitor)) {
+				continue
+			}
+			for i := len(monitor.Modes) - 1; i >= 0; i-- {
+				video := monitor.Modes[i]
+				selected := (current == ModeFullscreen) && (monitor.Name == currentMonitor) && (video == monitor.Current)
+				if imgui.MenuItemBoolV(video.String(), "", selected, true) {
+					switcher.set(ModeFullscreen, monitor.Name, video)
+				}
+			}
+			imgui.EndMenu()
+		}
+		imgui.EndMenu()
+	}
+	if switcher.err != nil {
+		imgui.Separator()
+		imgui.TextDisabled(switcher.err.Error())
+	}
+	imgui.EndMenu()
+}
+
+func (switcher *Switcher) set(mode Mode, monitor string, video VideoMode) {
+	switcher.err = switcher.controller.SetDisplayMode(mode, monitor, video)
+	if (switcher.err == nil) && (mode != ModeWindowed) {
+		switcher.fullscreenMode = mode
+		switcher.fullscreenMonitor = monitor
+		switcher.fullscreenVideo = video
+	}
+}
+
+func monitorLabel(monitor Monitor) string {
+	label := monitor.Name + " (" + monitor.Current.String() + ")"
+	if monitor.Primary {
+		label += " - primary"
+	}
+	// Identical monitors share their name, the position keeps the labels apart.
+	return fmt.Sprintf("%s##%d,%d", label, monitor.X, monitor.Y)
+}
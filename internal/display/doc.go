@@ -0,0 +1,5 @@
+// Package display switches the main window between windowed, borderless fullscreen
+// and exclusive fullscreen on a chosen monitor. Platforms provide the monitors and
+// the actual switch through the Controller interface; the Switcher adds a menu and
+// a hotkey toggle on top of it.
+package display
@@ -0,0 +1,59 @@
+package events
+
+import (
+	"github.com/AllenDang/cimgui-go"
+)
+
+// DropTargets offers the files dropped in the current frame to widgets, similar to the drag and drop API of imgui.
+// The platform places the mouse cursor at the position of the drop for the frame, so that the regular
+// hover tests of imgui determine the target. The first target that accepts the drop receives the files.
+// DropTargets must only be used from the thread that runs the frame loop.
+type DropTargets struct {
+	pending  []Drop
+	accepted []bool
+}
+
+// NewFrame replaces the drops offered to the targets. It must be called before the widgets are built.
+func (targets *DropTargets) NewFrame(drops []Drop) {
+	targets.pending = drops
+	targets.accepted = make([]bool, len(drops))
+}
+
+// AcceptItem returns the files dropped onto the last item, such as a button or a child window.
+// Call it right after the item, in the same way as imgui.BeginDragDropTarget().
+func (targets *DropTargets) AcceptItem() ([]string, bool) {
+	if !imgui.IsItemHoveredV(imgui.HoveredFlagsAllowWhenBlockedByActiveItem) {
+		return nil, false
+	}
+	return targets.accept()
+}
+
+// AcceptWindow returns the files dropped anywhere onto the current window, including its child windows.
+// Call it after the items of the window, so that they can accept the files first.
+func (targets *DropTargets) AcceptWindow() ([]string, bool) {
+	if !imgui.IsWindowHoveredV(imgui.HoveredFlagsChildWindows | imgui.HoveredFlagsAllowWhenBlockedByActiveItem) {
+		return nil, false
+	}
+	return targets.accept()
+}
+
+// Unaccepted returns the drops that no target accepted in the current frame.
+func (targets *DropTargets) Unaccepted() []Drop {
+	var drops []Drop
+	for index, drop := range targets.pending {
+		if !targets.accepted[index] {
+			drops = append(drops, drop)
+		}
+	}
+	return drops
+}
+
+func (targets *DropTargets) accept() ([]string, bool) {
+	for index, drop := range targets.pending {
+		if !targets.accepted[index] {
+			targets.accepted[index] = true
+			return drop.Paths, true
+		}
+	}
+	return nil, false
+}
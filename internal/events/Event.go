@@ -0,0 +1,107 @@
+package events
+
+import (
+	"github.com/AllenDang/cimgui-go"
+)
+
+// Event is a notification of the platform. Use a type switch to determine the kind of event.
+type Event interface {
+	isEvent()
+}
+
+// Action describes the change of a key or a mouse button.
+type Action int
+
+// This is a list of Action constants.
+const (
+	ActionRelease Action = iota
+	ActionPress
+	// ActionRepeat is reported while a key is held down, at the repeat rate of the system.
+	ActionRepeat
+)
+
+// Modifiers is a combination of the modifier keys that are held down.
+type Modifiers int
+
+// This is a list of Modifiers flags.
+const (
+	ModShift Modifiers = 1 << iota
+	ModCtrl
+	ModAlt
+	ModSuper
+)
+
+// Key is the event of a key that is pressed, repeated, or released.
+type Key struct {
+	// Key identifies the key by its position on a US keyboard. It is imgui.KeyNone for keys unknown to imgui.
+	Key imgui.Key
+	// Scancode is the platform specific code of the key.
+	Scancode int
+	Action   Action
+	Mods     Modifiers
+}
+
+// Char is the event of a character that is entered as text.
+type Char struct {
+	Char rune
+}
+
+// MouseMove is the event of the mouse cursor moving.
+type MouseMove struct {
+	// Pos is the position of the cursor in imgui coordinates.
+	// Both components are -math.MaxFloat32 if the cursor left the windows of the application.
+	Pos imgui.Vec2
+}
+
+// MouseButton is the event of a mouse button that is pressed or released.
+type MouseButton struct {
+	// Button is the index of the button as used by imgui: 0 is the primary, 1 the secondary, and 2 the tertiary button.
+	Button int
+	Action Action
+	Mods   Modifiers
+}
+
+// Scroll is the event of the mouse wheel or a touch pad scrolling.
+type Scroll struct {
+	X float32
+	Y float32
+}
+
+// Focus is the event of the application gaining or losing the input focus.
+type Focus struct {
+	Focused bool
+}
+
+// Resize is the event of the main window changing its size.
+type Resize struct {
+	// Size is the new size in screen coordinates.
+	Size imgui.Vec2
+}
+
+// Drop is the event of files that are dropped onto a window.
+// The platform emits a MouseMove to the position of the drop right before.
+type Drop struct {
+	// Paths lists the dropped files and directories.
+	Paths []string
+	// Pos is the position of the mouse cursor at the time of the drop, in imgui coordinates.
+	Pos imgui.Vec2
+}
+
+// Close is the event of the user requesting to close the main window.
+// The window stays open until the application stops the platform.
+type Close struct{}
+
+// Refresh is the event of a window losing its content, for example when it was uncovered.
+// The content must be rendered again, even if it did not change.
+type Refresh struct{}
+
+func (Key) isEvent()         {}
+func (Char) isEvent()        {}
+func (MouseMove) isEvent()   {}
+func (MouseButton) isEvent() {}
+func (Scroll) isEvent()      {}
+func (Focus) isEvent()       {}
+func (Resize) isEvent()      {}
+func (Drop) isEvent()        {}
+func (Close) isEvent()       {}
+func (Refresh) isEvent()     {}
@@ -0,0 +1,44 @@
+package events
+
+import (
+	"github.com/AllenDang/cimgui-go"
+)
+
+// modifierKeys maps the modifier flags to the keys imgui tracks them with.
+var modifierKeys = []struct {
+	flag Modifiers
+	key  imgui.Key
+}{
+	{flag: ModCtrl, key: imgui.ModCtrl},
+	{flag: ModShift, key: imgui.ModShift},
+	{flag: ModAlt, key: imgui.ModAlt},
+	{flag: ModSuper, key: imgui.ModSuper},
+}
+
+// Forward passes the event on to the imgui IO. Events that imgui has no use for are ignored.
+func Forward(io imgui.IO, event Event) {
+	switch event := event.(type) {
+	case Key:
+		forwardModifiers(io, event.Mods)
+		if event.Key != imgui.KeyNone {
+			io.AddKeyEvent(event.Key, event.Action != ActionRelease)
+		}
+	case Char:
+		io.AddInputCharacter(uint32(event.Char))
+	case MouseMove:
+		io.AddMousePosEvent(event.Pos.X, event.Pos.Y)
+	case MouseButton:
+		forwardModifiers(io, event.Mods)
+		io.AddMouseButtonEvent(int32(event.Button), event.Action != ActionRelease)
+	case Scroll:
+		io.AddMouseWheelEvent(event.X, event.Y)
+	case Focus:
+		io.AddFocusEvent(event.Focused)
+	}
+}
+
+func forwardModifiers(io imgui.IO, mods Modifiers) {
+	for _, modifier := range modifierKeys {
+		io.AddKeyEvent(modifier.key, (mods&modifier.flag) != 0)
+	}
+}
@@ -0,0 +1,10 @@
+// Package events describes the notifications that platforms deliver to the application:
+// input such as keys, characters, mouse movement, buttons and scrolling, changes of the focus
+// and size of the window, requests to close or refresh a window, and files dropped onto it.
+//
+// Events are collected by the platform while processing its window events and are handed to the
+// application once per frame, which may filter them before Forward passes them on to imgui.
+// Platforms do not feed imgui themselves, so an application sees every event first.
+// A request to close the window is only an event; the window stays open until the application
+// stops the platform. Dropped files are offered to the widgets of the frame through DropTargets.
+package events
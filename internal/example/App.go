@@ -0,0 +1,268 @@
+package example
+
+import (
+	"fmt"
+
+	"github.com/AllenDang/cimgui-go"
+	"github.com/ptxmac/cimgui-go-examples/internal/dialogs"
+	"github.com/ptxmac/cimgui-go-examples/internal/dispatch"
+	"github.com/ptxmac/cimgui-go-examples/internal/display"
+	"github.com/ptxmac/cimgui-go-examples/internal/events"
+	"github.com/ptxmac/cimgui-go-examples/internal/filedialog"
+	"github.com/ptxmac/cimgui-go-examples/internal/fonts"
+	"github.com/ptxmac/cimgui-go-examples/internal/layouts"
+	"github.com/ptxmac/cimgui-go-examples/internal/profiler"
+	"github.com/ptxmac/cimgui-go-examples/internal/redraw"
+	"github.com/ptxmac/cimgui-go-examples/internal/settings"
+	"github.com/ptxmac/cimgui-go-examples/internal/tasks"
+	"github.com/ptxmac/cimgui-go-examples/internal/textures"
+)
+
+// App provides the content that Run hosts in the main window.
+// Apps may implement further interfaces of this package, such as LayoutBuilder or MenuProvider, to extend the host.
+type App interface {
+	// Frame builds the user interface of one frame. It is called between imgui.NewFrame() and imgui.Render().
+	Frame(host *Host)
+}
+
+// LayoutBuilder is implemented by apps that provide a default docking layout.
+type LayoutBuilder interface {
+	// BuildLayout splits the dock space node and docks the windows of the app into the resulting nodes.
+	// It is called with docking enabled if no layout exists, or the user resets the layout.
+	BuildLayout(dockspace imgui.ID)
+}
+
+// MenuProvider is implemented by apps that add menus to the main menu bar.
+// The main menu bar is shown if the app provides menus, or with docking enabled.
+type MenuProvider interface {
+	// Menus adds the menus of the app. It is called within the main menu bar.
+	Menus(host *Host)
+}
+
+// EventFilter is implemented by apps that observe or intercept the events of the platform,
+// for example for global hotkeys or to control a 3D camera.
+type EventFilter interface {
+	// FilterEvent is called for every event, before it is forwarded to imgui. Returning false swallows the event.
+	// It is called before the frame is started; WantCaptureMouse and WantCaptureKeyboard of the imgui IO
+	// still tell whether imgui used the mouse or keyboard in the previous frame, and thus wants the event.
+	FilterEvent(host *Host, event events.Event) bool
+}
+
+// DropHandler is implemented by apps that handle files dropped onto the main window.
+type DropHandler interface {
+	// FilesDropped is called with the drops that no drop target accepted during the frame.
+	// It is called after Frame, before imgui.Render().
+	FilesDropped(host *Host, drop events.Drop)
+}
+
+// CloseHandler is implemented by apps that want to confirm the end of the program,
+// for example to ask the user whether to save unsaved changes.
+type CloseHandler interface {
+	// CloseRequested is called when the user requests to close the main window, or the program receives
+	// an interrupt or termination signal. Returning true ends the program after the current frame.
+	// Returning false vetoes the request; the app may later call Host.Quit() to end the program after all.
+	// It is called before the frame is started, so dialogs must be opened in the following Frame call.
+	CloseRequested(host *Host) bool
+}
+
+// ShutdownHandler is implemented by apps that release resources or store their state when the program ends.
+type ShutdownHandler interface {
+	// Shutdown is called once after the last frame, also if the program loop ends due to a failure.
+	// Textures, fonts and settings are still available; the settings are saved after Shutdown returns.
+	// An error is returned by Run, unless the loop already failed.
+	Shutdown(host *Host) error
+}
+
+// Host gives an App access to the services of the program loop.
+type Host struct {
+	app          App
+	platform     Platform
+	renderer     Renderer
+	fontSet      *fonts.Set
+	fontErr      error
+	fontSize     float32
+	scale        *scaling
+	textureCache *textures.Cache
+	textureErr   error
+	layouts      *layouts.Manager
+	display      *display.Switcher
+	decorator    WindowDecorator
+	drops        events.DropTargets
+	settings     *settings.Store
+	settingsErr  error
+	clearColor   [3]float32
+	dispatcher   *dispatch.Dispatcher
+	tasks        *tasks.Manager
+	dialogs      *dialogs.Service
+	fileDialog   *filedialog.Dialog
+	fileSystem   filedialog.DirFS
+	profiler     *profiler.Profiler
+	redraw       *redraw.Detector
+
+	shutdownHooks []func() error
+}
+
+// Platform returns the platform of the program loop.
+func (host *Host) Platform() Platform {
+	return host.platform
+}
+
+// Renderer returns the renderer of the program loop. It wraps the renderer given to Run, so that
+// textures created, updated, or deleted through it cause the next frame to be rendered.
+func (host *Host) Renderer() Renderer {
+	return host.renderer
+}
+
+// Fonts returns the font set of the main text, and the error if the configured fonts could not be loaded.
+// In case of an error, the set falls back to the default font of imgui.
+func (host *Host) Fonts() (*fonts.Set, error) {
+	return host.fontSet, host.fontErr
+}
+
+// FontSize returns the pixel height of the text, before scaling.
+func (host *Host) FontSize() float32 {
+	return host.fontSize
+}
+
+// SetFontSize changes the pixel height of the text. The font atlas is rebuilt before the next frame.
+func (host *Host) SetFontSize(sizePixels float32) {
+	host.fontSize = sizePixels
+	host.fontSet.SetSize(sizePixels * host.scale.FontScale())
+}
+
+// Textures returns the cache of the textures of the embedded assets, and the error of the last reload, if any.
+// The error stays until a later reload succeeds, or until it is dismissed.
+func (host *Host) Textures() (*textures.Cache, error) {
+	return host.textureCache, host.textureErr
+}
+
+// DismissTextureError clears the error of the last reload of textures.
+func (host *Host) DismissTextureError() {
+	host.textureErr = nil
+}
+
+// Layouts returns the manager of the docking layouts. It is nil if docking is not enabled.
+func (host *Host) Layouts() *layouts.Manager {
+	return host.layouts
+}
+
+// Display returns the switcher of the display mode. It is nil if the platform does not support display modes.
+// With a switcher, the main menu bar offers the display modes, and F11 toggles fullscreen.
+func (host *Host) Display() *display.Switcher {
+	return host.display
+}
+
+// Dispatcher returns the queue of tasks that run on the thread of the program loop, between two frames.
+// Goroutines use it to update the state of the app, which the frame loop reads without locks.
+func (host *Host) Dispatcher() *dispatch.Dispatcher {
+	return host.dispatcher
+}
+
+// Tasks returns the manager of the background tasks, whose progress is shown in the status bar.
+func (host *Host) Tasks() *tasks.Manager {
+	return host.tasks
+}
+
+// Dialogs returns the service that shows modal dialogs, for example to confirm an action.
+// Goroutines may ask for dialogs as well; they receive the answer of the user on a channel.
+func (host *Host) Dialogs() *dialogs.Service {
+	return host.dialogs
+}
+
+// FileDialog returns the dialog to choose files to open or save, and the file system it browses.
+// The paths of its results are relative to the file system; its Path method returns the paths of the operating system.
+func (host *Host) FileDialog() (*filedialog.Dialog, filedialog.DirFS) {
+	return host.fileDialog, host.fileSystem
+}
+
+// Profiler returns the profiler that measures the frames. Its overlay is toggled with F12.
+func (host *Host) Profiler() *profiler.Profiler {
+	return host.profiler
+}
+
+// Redraw returns the detector of unchanged frames, which Run does not render again.
+// Apps call ForceRedraw on it if their content changes without a change of the draw data.
+func (host *Host) Redraw() *redraw.Detector {
+	return host.redraw
+}
+
+// Drops returns the targets for files dropped onto the window in the current frame.
+// Widgets accept the files with the methods of the targets, right after they were built.
+func (host *Host) Drops() *events.DropTargets {
+	return &host.drops
+}
+
+// Title returns the title of the main window. It is empty if the platform does not support titles.
+func (host *Host) Title() string {
+	if host.decorator == nil {
+		return ""
+	}
+	return host.decorator.Title()
+}
+
+// SetTitle changes the title of the main window, for example to show the open document and whether it has unsaved changes.
+func (host *Host) SetTitle(title string) {
+	if host.decorator != nil {
+		host.decorator.SetTitle(title)
+	}
+}
+
+// RequestAttention highlights the main window if it does not have the focus, for example when a long task has finished.
+func (host *Host) RequestAttention() {
+	if host.decorator != nil {
+		host.decorator.RequestAttention()
+	}
+}
+
+// Quit ends the program after the current frame, without asking the app for confirmation.
+func (host *Host) Quit() {
+	host.platform.Stop()
+}
+
+func (host *Host) requestClose() {
+	if handler, handles := host.app.(CloseHandler); handles && !handler.CloseRequested(host) {
+		return
+	}
+	host.Quit()
+}
+
+// OnShutdown registers a function that is called when the program loop ends, after the Shutdown of the app.
+// Hooks are called in reverse order of their registration. The first error is returned by Run.
+func (host *Host) OnShutdown(hook func() error) {
+	host.shutdownHooks = append(host.shutdownHooks, hook)
+}
+
+func (host *Host) shutdown() error {
+	var err error
+	if handler, handles := host.app.(ShutdownHandler); handles {
+		err = handler.Shutdown(host)
+	}
+	for i := len(host.shutdownHooks) - 1; i >= 0; i-- {
+		if hookErr := host.shutdownHooks[i](); err == nil {
+			err = hookErr
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to shut down: %w", err)
+	}
+	return nil
+}
+
+// Settings returns the store of the settings that persist between sessions, and the error of the last
+// load or save, if any. Apps register their own sections with the store, for example the state of their panels.
+func (host *Host) Settings() (*settings.Store, error) {
+	return host.settings, host.settingsErr
+}
+
+// ClearColor returns the color the main window is cleared with.
+func (host *Host) ClearColor() [3]float32 {
+	return host.clearColor
+}
+
+// SetClearColor sets the color the main window is cleared with.
+func (host *Host) SetClearColor(color [3]float32) {
+	if color != host.clearColor {
+		host.redraw.ForceRedraw()
+	}
+	host.clearColor = color
+}
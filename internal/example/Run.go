@@ -1,23 +1,50 @@
 package example
 
 import (
+	"context"
 	"fmt"
+	"image"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/AllenDang/cimgui-go"
-	"github.com/ptxmac/cimgui-go-examples/internal/demo"
+	"github.com/ptxmac/cimgui-go-examples/internal/dialogs"
+	"github.com/ptxmac/cimgui-go-examples/internal/dispatch"
+	"github.com/ptxmac/cimgui-go-examples/internal/display"
+	"github.com/ptxmac/cimgui-go-examples/internal/events"
+	"github.com/ptxmac/cimgui-go-examples/internal/fonts"
+	"github.com/ptxmac/cimgui-go-examples/internal/layouts"
+	"github.com/ptxmac/cimgui-go-examples/internal/profiler"
+	"github.com/ptxmac/cimgui-go-examples/internal/redraw"
+	"github.com/ptxmac/cimgui-go-examples/internal/settings"
+	"github.com/ptxmac/cimgui-go-examples/internal/tasks"
+	"github.com/ptxmac/cimgui-go-examples/internal/textures"
 )
 
 // Platform covers mouse/keyboard/gamepad inputs, cursor shape, timing, windowing.
 type Platform interface {
+	// Dispose releases the resources of the platform. Run calls it when the program loop ends.
+	Dispose()
+	// Err returns a failure of the platform that prevents further frames, or nil. It is called once per frame.
+	Err() error
 	// ShouldStop is regularly called as the abort condition for the program loop.
 	ShouldStop() bool
+	// Stop lets ShouldStop return true. Requests of the user to close the window are reported as events.Close,
+	// instead of stopping the platform directly.
+	Stop()
 	// ProcessEvents is called once per render loop to dispatch any pending events.
 	ProcessEvents()
+	// Events returns the events that occurred since the last call. It is called after ProcessEvents.
+	// The platform does not forward the events to imgui itself, this is up to the caller.
+	Events() []events.Event
 	// DisplaySize returns the dimension of the display.
 	DisplaySize() [2]float32
 	// FramebufferSize returns the dimension of the framebuffer.
 	FramebufferSize() [2]float32
+	// ContentScale returns the ratio between the current DPI and the default DPI of the platform.
+	ContentScale() float32
 	// NewFrame marks the begin of a render pass. It must update the cimgui IO state according to user input (mouse, keyboard, ...)
 	NewFrame()
 	// PostRender marks the completion of one render pass. Typically this causes the display buffer to be swapped.
@@ -40,100 +67,368 @@ func (board clipboard) SetText(text string) {
 	board.platform.SetClipboardText(text)
 }
 
+// WindowDecorator is implemented by platforms that can change the appearance of their window in the task bar.
+type WindowDecorator interface {
+	// Title returns the current title of the window.
+	Title() string
+	// SetTitle changes the title of the window.
+	SetTitle(title string)
+	// SetIcon sets the icon of the window from images of different sizes.
+	SetIcon(images []image.Image)
+	// RequestAttention highlights the window if it does not have the focus.
+	RequestAttention()
+}
+
+// WindowStateKeeper is implemented by platforms that can restore the state of their window in a later session.
+type WindowStateKeeper interface {
+	// LoadWindowState restores the window state from the settings, if they contain one.
+	LoadWindowState(store *settings.Store) error
+	// SaveWindowState puts the current window state into the settings.
+	SaveWindowState(store *settings.Store) error
+}
+
+// ViewportLister is implemented by platforms that support secondary viewports.
+// Run compares the draw data of all viewports to find out whether a frame needs to be rendered.
+type ViewportLister interface {
+	// Viewports returns all viewports of imgui in a stable order, starting with the main viewport.
+	Viewports() []imgui.Viewport
+}
+
 // Renderer covers rendering cimgui draw data.
 type Renderer interface {
+	textures.Renderer
+
+	// Dispose releases the resources of the renderer. Run calls it when the program loop ends, before the platform is disposed.
+	Dispose()
+	// Err returns a failure of the renderer that prevents further frames, or nil. It is called once per frame.
+	Err() error
+	// NewFrame prepares the renderer for a new frame. It creates the font texture if the font atlas has none yet.
+	NewFrame()
+	// RebuildFonts replaces the font texture with the current content of the font atlas.
+	RebuildFonts()
 	// PreRender causes the display buffer to be prepared for new output.
 	PreRender(clearColor [3]float32)
 	// Render draws the provided cimgui draw data.
-	Render(displaySize [2]float32, framebufferSize [2]float32, drawData imgui.DrawData)
+	// The projection is derived from the display position, display size and framebuffer scale of the draw data.
+	Render(drawData imgui.DrawData)
 }
 
 const (
-	millisPerSecond = 1000
-	sleepDuration   = time.Millisecond * 25
+	millisPerSecond  = 1000
+	sleepDuration    = time.Millisecond * 25
+	fullscreenHotkey = imgui.KeyF11
+	profilerHotkey   = imgui.KeyF12
+	profilerHistory  = 300
 )
 
-// Run implements the main program loop of the demo. It returns when the platform signals to stop.
-// This demo application shows some basic features of ImGui, as well as exposing the standard demo window.
-func Run(p Platform, r Renderer) {
+// Run implements the main program loop of the demo. It returns when the platform signals to stop,
+// or when the context is canceled; in both cases without an error. It returns an error if the
+// platform or the renderer fail, or if the shutdown of the App, loading or saving the settings fail.
+// Settings that failed to load are not saved, so that they are not overwritten with defaults.
+// The content of the main window is provided by an App, which defaults to a showcase of some basic
+// features of ImGui, as well as exposing the standard demo window.
+//
+// Run takes over the platform and the renderer, and disposes them before it returns.
+// When the loop ends, the shutdown hooks run first, then the settings are saved, and finally
+// the resources are released in reverse order of their creation, with the renderer before the platform.
+func Run(ctx context.Context, p Platform, r Renderer, options ...Option) (err error) {
+	// The renderer holds resources of the OpenGL context, which is destroyed with the window of the platform.
+	defer p.Dispose()
+	defer r.Dispose()
+
+	opts := newOptions(options)
+	app := opts.app
 
 	// TODO:
 	//cimgui.CurrentIO().SetClipboard(clipboard{platform: p})
 
-	showDemoWindow := false
-	showGoDemoWindow := false
-	clearColor := [3]float32{0, 0, 0}
-	f := float32(0)
-	counter := 0
-	showAnotherWindow := false
+	host := &Host{
+		app:      app,
+		platform: p,
+		fontSize: opts.fontSize,
+		scale:    newScaling(opts.scale),
+		profiler: profiler.NewProfiler(profilerHistory, profilerHotkey),
+		redraw:   redraw.NewDetector(),
+	}
+	prof := host.profiler
+	// Apps reach the renderer through the host, so their texture changes force a redraw as well.
+	host.renderer = redrawingRenderer{Renderer: r, textures: host.redraw.Textures(r)}
 
-	for !p.ShouldStop() {
-		p.ProcessEvents()
+	fontManager := fonts.NewManager(imgui.CurrentIO().Fonts())
+	defer fontManager.Dispose()
+	// The base font is merged with fallback fonts for scripts it does not cover.
+	// Only glyphs of announced texts are added to the atlas, keeping it small.
+	host.fontSet, host.fontErr = fonts.NewSet(fontManager, host.fontSize, opts.baseFont, opts.fallbackFonts...)
+	if host.fontErr != nil {
+		host.fontSet, _ = fonts.NewSet(fontManager, host.fontSize, fonts.Face{})
+	}
 
-		// Signal start of a new frame
-		p.NewFrame()
-		imgui.NewFrame()
+	if opts.viewports {
+		io := imgui.CurrentIO()
+		io.SetConfigFlags(io.ConfigFlags() | imgui.ConfigFlagsViewportsEnable)
+		// Platform windows look identical to regular ones when the imgui windows are not rounded.
+		imgui.CurrentStyle().SetWindowRounding(0)
+	}
 
-		// 1. Show a simple window.
-		// Tip: if we don't call cimgui.Begin()/cimgui.End() the widgets automatically appears in a window called "Debug".
-		{
-			imgui.Text("ภาษาไทย测试조선말")                                               // To display these, you'll need to register a compatible font
-			imgui.Text("Hello, world!")                                              // Display some text
-			imgui.SliderFloatV("float", &f, 0.0, 1.0, "%.3f", imgui.SliderFlagsNone) // Edit 1 float using a slider from 0.0f to 1.0f
+	if opts.docking {
+		io := imgui.CurrentIO()
+		io.SetConfigFlags(io.ConfigFlags() | imgui.ConfigFlagsDockingEnable)
+		var builder layouts.Builder
+		if layoutBuilder, provided := app.(LayoutBuilder); provided {
+			builder = layoutBuilder.BuildLayout
+		}
+		host.layouts = layouts.NewManager(opts.configPath("layouts"), builder)
+	}
 
-			imgui.ColorEdit3("clear color", &clearColor) // Edit 3 floats representing a color
+	if controller, supported := p.(display.Controller); supported {
+		host.display = display.NewSwitcher(controller, fullscreenHotkey)
+	}
+	menus, providesMenus := app.(MenuProvider)
 
-			imgui.Checkbox("Demo Window", &showDemoWindow) // Edit bools storing our window open/close state
-			imgui.Checkbox("Go Demo Window", &showGoDemoWindow)
-			imgui.Checkbox("Another Window", &showAnotherWindow)
+	host.textureCache = textures.NewCache(host.renderer, opts.textureFS,
+		textures.CacheConfig{ReloadInterval: opts.textureReload})
+	defer host.textureCache.Dispose()
 
-			if imgui.Button("Button") { // Buttons return true when clicked (most widgets return true when edited/activated)
-				counter++
+	host.settings = settings.NewStore(settings.Config{
+		IniPath:    opts.iniPath,
+		Path:       opts.configPath("settings.json"),
+		Version:    opts.settingsVersion,
+		Migrations: opts.settingsMigrations,
+	})
+	loadErr := host.settings.Load()
+	host.settingsErr = loadErr
+	host.fileDialog, host.fileSystem = newFileDialog()
+	if err := host.settings.Register(fileDialogKey, host.fileDialog.State()); host.settingsErr == nil {
+		host.settingsErr = err
+	}
+	keeper, keepsWindowState := p.(WindowStateKeeper)
+	keepsWindowState = keepsWindowState && opts.keepWindowState
+	if keepsWindowState && (host.settingsErr == nil) {
+		host.settingsErr = keeper.LoadWindowState(host.settings)
+	}
+	defer func() {
+		// Settings that failed to load are kept as they are. They may be of a newer version, or contain
+		// sections that were not registered in this session, which saving would discard.
+		if loadErr != nil {
+			if err == nil {
+				err = fmt.Errorf("settings not saved, as loading them failed: %w", loadErr)
 			}
-			imgui.SameLine()
-			imgui.Text(fmt.Sprintf("counter = %d", counter))
+			return
+		}
+		// The first error is reported; the previous settings stay in place if saving fails.
+		var saveErr error
+		if keepsWindowState {
+			saveErr = keeper.SaveWindowState(host.settings)
+		}
+		if saveErr == nil {
+			saveErr = host.settings.Save()
+		}
+		if (err == nil) && (saveErr != nil) {
+			err = fmt.Errorf("failed to save settings: %w", saveErr)
+		}
+	}()
+	// Shutdown hooks may still change settings, so they run before the settings are saved.
+	defer func() {
+		if shutdownErr := host.shutdown(); err == nil {
+			err = shutdownErr
+		}
+	}()
 
-			imgui.Text(fmt.Sprintf("Application average %.3f ms/frame (%.1f FPS)",
-				millisPerSecond/imgui.CurrentIO().Framerate(), imgui.CurrentIO().Framerate()))
+	if decorator, supported := p.(WindowDecorator); supported {
+		host.decorator = decorator
+		// Without a valid icon, the window keeps the default one of the system.
+		icons, err := loadImages(opts.iconFS, opts.iconPaths)
+		if err == nil {
+			decorator.SetIcon(icons)
 		}
+	}
+
+	// Tasks posted from other goroutines run between frames. Goroutines that outlive
+	// the loop can not post anymore; their tasks are dropped.
+	host.dispatcher = dispatch.NewDispatcher()
+	// Running tasks are canceled when the loop ends, and must return before the resources are released.
+	host.tasks = tasks.NewManager(host.dispatcher)
+	defer host.tasks.Close()
+	// The dispatcher is closed before the tasks are waited for, as a task that waits in PostAndWait
+	// would otherwise never return: the loop that runs the posted functions has ended already.
+	defer host.dispatcher.Close()
+	// Dialogs that are still open when the loop ends are canceled.
+	host.dialogs = dialogs.NewService(host.dispatcher)
+	defer host.dialogs.Close()
+
+	eventFilter, filtersEvents := app.(EventFilter)
+	dropHandler, handlesDrops := app.(DropHandler)
+
+	// Signals from the terminal are handled like requests to close the window.
+	// A second signal stops the program without asking the app again.
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	signalled := false
 
-		// 2. Show another simple window. In most cases you will use an explicit Begin/End pair to name your windows.
-		if showAnotherWindow {
-			// Pass a pointer to our bool variable (the window will have a closing button that will clear the bool when clicked)
-			imgui.BeginV("Another window", &showAnotherWindow, 0)
-			imgui.Text("Hello from another window!")
-			if imgui.Button("Close Me") {
-				showAnotherWindow = false
+	for !p.ShouldStop() && (ctx.Err() == nil) {
+		prof.BeginFrame()
+		p.ProcessEvents()
+		// The app sees the events first and decides which of them reach imgui.
+		var drops []events.Drop
+		for _, event := range p.Events() {
+			if filtersEvents && !eventFilter.FilterEvent(host, event) {
+				continue
+			}
+			events.Forward(imgui.CurrentIO(), event)
+			switch event := event.(type) {
+			case events.Drop:
+				drops = append(drops, event)
+			case events.Close:
+				host.requestClose()
+			case events.Refresh:
+				host.redraw.ForceRedraw()
+			}
+		}
+		select {
+		case <-signals:
+			if signalled {
+				p.Stop()
 			}
-			imgui.End()
+			signalled = true
+			host.requestClose()
+		default:
+		}
+		host.dispatcher.RunPending()
+		host.drops.NewFrame(drops)
+		// The error of a failed reload is kept until a later reload succeeds, or the app dismisses it.
+		if reloaded, err := host.textureCache.Poll(); reloaded > 0 {
+			host.textureErr = err
 		}
 
-		// 3. Show the ImGui demo window. Most of the sample code is in cimgui.ShowDemoWindow().
-		// Read its code to learn more about Dear ImGui!
-		if showDemoWindow {
-			// Normally user code doesn't need/want to call this because positions are saved in .ini file anyway.
-			// Here we just want to make the demo initial state a bit more friendly!
-			const demoX = 650
-			const demoY = 20
-			imgui.SetNextWindowPosV(imgui.Vec2{X: demoX, Y: demoY}, imgui.CondFirstUseEver, imgui.Vec2{})
+		// Signal start of a new frame
+		prof.Begin(profiler.PhaseNewFrame)
+		p.NewFrame()
+		if host.scale.update(p.ContentScale(), imgui.CurrentIO().DisplayFramebufferScale()) {
+			host.fontSet.SetSize(host.fontSize * host.scale.FontScale())
+		}
+		if fontManager.Apply() { // Font changes must be applied before the renderer prepares the frame
+			// The atlas is built already, so the renderer cannot tell that its texture is outdated.
+			r.RebuildFonts()
+			host.redraw.ForceRedraw()
+		}
+		if host.layouts != nil {
+			host.layouts.Apply() // Layouts must be loaded between frames as well
+		}
+		r.NewFrame()
+		imgui.NewFrame()
 
-			imgui.ShowDemoWindowV(&showDemoWindow)
+		prof.Begin(profiler.PhaseLayout)
+		if (host.layouts != nil) || providesMenus {
+			if imgui.BeginMainMenuBar() {
+				if host.layouts != nil {
+					host.layouts.Menu()
+				}
+				if host.display != nil {
+					host.display.Menu()
+				}
+				if providesMenus {
+					menus.Menus(host)
+				}
+				imgui.EndMainMenuBar()
+			}
+		}
+		host.tasks.StatusBar() // The status bar must be placed before the dock space, just like the main menu bar
+		if host.display != nil {
+			host.display.HandleHotkey()
 		}
-		if showGoDemoWindow {
-			demo.Show(&showGoDemoWindow)
+		prof.HandleHotkey()
+		if host.layouts != nil {
+			host.layouts.Dialogs()
+			host.layouts.DockSpace()
+		}
+
+		app.Frame(host)
+		host.tasks.Window()
+		host.dialogs.Frame()
+		host.fileDialog.Frame()
+		prof.Overlay()
+		if handlesDrops {
+			for _, drop := range host.drops.Unaccepted() {
+				dropHandler.FilesDropped(host, drop)
+			}
 		}
 
 		// Rendering
+		prof.Begin(profiler.PhaseImguiRender)
 		imgui.Render() // This call only creates the draw data list. Actual rendering to framebuffer is done below.
+		if err := host.settings.SaveIniIfWanted(); err != nil {
+			host.settingsErr = err
+		}
 
-		r.PreRender(clearColor)
-		// A this point, the application could perform its own rendering...
-		// app.RenderScene()
+		prof.CountDrawData(imgui.CurrentDrawData())
 
-		r.Render(p.DisplaySize(), p.FramebufferSize(), imgui.CurrentDrawData())
-		p.PostRender()
+		// Frames that look exactly like the previous one are neither rendered nor swapped.
+		// The windows of the viewports are updated regardless, as they may have been moved or resized.
+		viewportsEnabled := (imgui.CurrentIO().ConfigFlags() & imgui.ConfigFlagsViewportsEnable) != 0
+		changed := true
+		if lister, lists := p.(ViewportLister); lists {
+			changed = host.redraw.Changed(viewportDrawData(lister.Viewports())...)
+		} else if !viewportsEnabled {
+			changed = host.redraw.Changed(imgui.CurrentDrawData())
+		}
+
+		prof.Begin(profiler.PhaseRender)
+		if changed {
+			r.PreRender(host.clearColor)
+			// A this point, the application could perform its own rendering...
+			// app.RenderScene()
 
-		// sleep to avoid 100% CPU usage for this demo
-		<-time.After(sleepDuration)
+			r.Render(imgui.CurrentDrawData())
+		}
+		// Update and render the windows of the viewports outside the main window.
+		if viewportsEnabled {
+			imgui.UpdatePlatformWindows()
+			if changed {
+				imgui.RenderPlatformWindowsDefault()
+			}
+		}
+		prof.Begin(profiler.PhasePresent)
+		if changed {
+			p.PostRender()
+		}
+		if err := p.Err(); err != nil {
+			return fmt.Errorf("platform failed: %w", err)
+		}
+		if err := r.Err(); err != nil {
+			return fmt.Errorf("renderer failed: %w", err)
+		}
+
+		// sleep to avoid 100% CPU usage for this demo, unless a goroutine posts a task or wakes the loop
+		prof.Begin(profiler.PhaseSleep)
+		host.dispatcher.Wait(ctx, sleepDuration)
+		prof.EndFrame()
+	}
+	return nil
+}
+
+func viewportDrawData(viewports []imgui.Viewport) []imgui.DrawData {
+	drawData := make([]imgui.DrawData, len(viewports))
+	for i, viewport := range viewports {
+		drawData[i] = viewport.DrawData()
 	}
+	return drawData
+}
+
+// redrawingRenderer passes the texture changes through the detector of unchanged frames.
+type redrawingRenderer struct {
+	Renderer
+	textures textures.Renderer
+}
+
+func (renderer redrawingRenderer) CreateTexture(pixels []uint8, width, height int) (imgui.TextureID, error) {
+	return renderer.textures.CreateTexture(pixels, width, height)
+}
+
+func (renderer redrawingRenderer) UpdateTexture(id imgui.TextureID, pixels []uint8, width, height int) error {
+	return renderer.textures.UpdateTexture(id, pixels, width, height)
+}
+
+func (renderer redrawingRenderer) DeleteTexture(id imgui.TextureID) {
+	renderer.textures.DeleteTexture(id)
 }
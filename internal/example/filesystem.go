@@ -0,0 +1,24 @@
+package example
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/ptxmac/cimgui-go-examples/internal/filedialog"
+)
+
+// fileDialogKey is the section of the settings that holds the state of the file dialog.
+const fileDialogKey = "fileDialog"
+
+// newFileDialog returns a dialog that browses the file system that contains the home directory of the user.
+// This is the whole file system on Unix, and the drive of the home directory on Windows.
+func newFileDialog() (*filedialog.Dialog, filedialog.DirFS) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home, _ = os.Getwd()
+	}
+	root := filepath.VolumeName(home) + string(filepath.Separator)
+	fsys := filedialog.NewDirFS(root)
+	homeName, _ := fsys.Name(home)
+	return filedialog.New(fsys, homeName), fsys
+}
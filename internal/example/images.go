@@ -0,0 +1,28 @@
+package example
+
+import (
+	"fmt"
+	"image"
+	"io/fs"
+
+	// Register the image formats that icons are commonly stored in.
+	_ "image/png"
+)
+
+// loadImages decodes the images at given paths.
+func loadImages(fsys fs.FS, paths []string) ([]image.Image, error) {
+	images := make([]image.Image, 0, len(paths))
+	for _, path := range paths {
+		file, err := fsys.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		img, _, err := image.Decode(file)
+		_ = file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+		}
+		images = append(images, img)
+	}
+	return images, nil
+}
@@ -0,0 +1,192 @@
+package example
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ptxmac/cimgui-go-examples/assets"
+	"github.com/ptxmac/cimgui-go-examples/internal/fonts"
+	"github.com/ptxmac/cimgui-go-examples/internal/settings"
+)
+
+// textureReloadInterval is the time between two checks for modified textures of WithTextureDir.
+const textureReloadInterval = time.Second
+
+// defaultAppName is the name of the directory that holds the configuration files of the examples.
+const defaultAppName = "cimgui-go-examples"
+
+// Option customizes the behavior of Run.
+type Option func(*options)
+
+type options struct {
+	scale             float32
+	fontSize          float32
+	baseFont          fonts.Face
+	fallbackFonts     []fonts.Face
+	searchSystemFonts bool
+	viewports         bool
+	docking           bool
+	app               App
+
+	appName            string
+	iniPath            string
+	iniPathSet         bool
+	settingsVersion    int
+	settingsMigrations map[int]settings.Migration
+	keepWindowState    bool
+	iconFS             fs.FS
+	iconPaths          []string
+	textureFS          fs.FS
+	textureReload      time.Duration
+}
+
+func newOptions(list []Option) options {
+	opts := options{
+		scale:             1,
+		fontSize:          fonts.DefaultSize,
+		searchSystemFonts: true,
+		appName:           defaultAppName,
+		iconFS:            assets.FS,
+		iconPaths:         []string{"icon-16.png", "icon-32.png", "icon-48.png", "icon-64.png"},
+		textureFS:         assets.FS,
+	}
+	for _, option := range list {
+		option(&opts)
+	}
+	if !opts.iniPathSet {
+		opts.iniPath = opts.configPath("imgui.ini")
+	}
+	if opts.app == nil {
+		opts.app = newShowcase()
+	}
+	if opts.searchSystemFonts {
+		opts.fallbackFonts = fonts.FindFallbackFonts(fonts.FallbackFamilies)
+	}
+	return opts
+}
+
+// WithScale sets an application specific scale factor for fonts and style.
+// It is applied on top of the content scale reported by the platform.
+func WithScale(factor float32) Option {
+	return func(opts *options) {
+		opts.scale = factor
+	}
+}
+
+// WithFontSize sets the initial pixel height of the text.
+func WithFontSize(sizePixels float32) Option {
+	return func(opts *options) {
+		opts.fontSize = sizePixels
+	}
+}
+
+// WithBaseFont sets the font that provides the glyphs for most of the text.
+// By default, the font embedded in imgui is used.
+func WithBaseFont(face fonts.Face) Option {
+	return func(opts *options) {
+		opts.baseFont = face
+	}
+}
+
+// WithFallbackFonts sets the fonts that provide the glyphs missing from the base font.
+// By default, the first installed font of each of fonts.FallbackFamilies is used.
+// Passing no faces disables fallback fonts.
+func WithFallbackFonts(faces ...fonts.Face) Option {
+	return func(opts *options) {
+		opts.fallbackFonts = faces
+		opts.searchSystemFonts = false
+	}
+}
+
+// WithViewports lets imgui windows be dragged out of the main window into windows of their own.
+// The platform must support viewports for this to have an effect.
+func WithViewports() Option {
+	return func(opts *options) {
+		opts.viewports = true
+	}
+}
+
+// WithApp sets the content of the main window. By default, a showcase of imgui features is shown.
+func WithApp(app App) Option {
+	return func(opts *options) {
+		opts.app = app
+	}
+}
+
+// WithDocking lets imgui windows be docked into each other and into the main window.
+// A main menu bar offers to save and restore named layouts in the configuration directory of the user.
+func WithDocking() Option {
+	return func(opts *options) {
+		opts.docking = true
+	}
+}
+
+// WithAppName sets the name of the application. The configuration files are stored in a directory of this name,
+// below the configuration directory of the user. On Linux, this is $XDG_CONFIG_HOME or ~/.config.
+func WithAppName(name string) Option {
+	return func(opts *options) {
+		opts.appName = name
+	}
+}
+
+// WithIniFile sets the path of the file that imgui stores its settings in, such as window positions.
+// By default, the file is stored in the configuration directory of the application.
+func WithIniFile(path string) Option {
+	return func(opts *options) {
+		opts.iniPath = path
+		opts.iniPathSet = true
+	}
+}
+
+// WithoutIniFile keeps the settings of imgui in memory only.
+func WithoutIniFile() Option {
+	return WithIniFile("")
+}
+
+// WithSettingsVersion sets the current version of the settings of the app,
+// and the migrations that upgrade settings of older versions.
+func WithSettingsVersion(version int, migrations map[int]settings.Migration) Option {
+	return func(opts *options) {
+		opts.settingsVersion = version
+		opts.settingsMigrations = migrations
+	}
+}
+
+// configPath returns the path of an entry in the configuration directory of the application.
+// It is empty if the configuration directory of the user is not known.
+func (opts options) configPath(elem ...string) string {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(append([]string{base, opts.appName}, elem...)...)
+}
+
+// WithWindowState restores the position, size, and maximized or fullscreen state of the main window
+// from the previous session, if the platform supports it. The state is kept in the settings.
+func WithWindowState() Option {
+	return func(opts *options) {
+		opts.keepWindowState = true
+	}
+}
+
+// WithIcon sets the icon of the main window from images of a file system, typically an embed.FS.
+// Images of different sizes, such as 16x16, 32x32 and 48x48, let the system pick the one closest to its needs.
+// By default, the icon of the embedded assets is used.
+func WithIcon(fsys fs.FS, paths ...string) Option {
+	return func(opts *options) {
+		opts.iconFS = fsys
+		opts.iconPaths = paths
+	}
+}
+
+// WithTextureDir loads the textures of the app from a directory on disk, instead of the embedded assets.
+// Textures are reloaded when their files change, which allows to edit images while the program runs.
+func WithTextureDir(dir string) Option {
+	return func(opts *options) {
+		opts.textureFS = os.DirFS(dir)
+		opts.textureReload = textureReloadInterval
+	}
+}
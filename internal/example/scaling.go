@@ -0,0 +1,55 @@
+package example
+
+import (
+	"github.com/AllenDang/cimgui-go"
+)
+
+// scaling keeps the size of fonts and style in line with the content scale of the platform.
+//
+// Fonts are rasterized for the pixel density of the display, so that they stay sharp.
+// Where the framebuffer is larger than the display (such as on macOS), imgui works
+// in display coordinates, and the fonts are scaled down again when drawn.
+type scaling struct {
+	factor float32
+
+	fontScale  float32
+	styleScale float32
+}
+
+func newScaling(factor float32) *scaling {
+	return &scaling{
+		factor:     factor,
+		fontScale:  1,
+		styleScale: 1,
+	}
+}
+
+// update applies the scale for given platform values to imgui.
+// It returns true if fonts must be rebuilt with the new FontScale().
+func (s *scaling) update(contentScale float32, framebufferScale imgui.Vec2) bool {
+	if contentScale <= 0 {
+		contentScale = 1
+	}
+	if framebufferScale.X <= 0 {
+		framebufferScale.X = 1
+	}
+
+	fontScale := contentScale * s.factor
+	styleScale := fontScale / framebufferScale.X
+	imgui.CurrentIO().SetFontGlobalScale(1 / framebufferScale.X)
+	if styleScale != s.styleScale {
+		// ScaleAllSizes() multiplies the current sizes, so only the change is applied.
+		imgui.CurrentStyle().ScaleAllSizes(styleScale / s.styleScale)
+		s.styleScale = styleScale
+	}
+	if fontScale == s.fontScale {
+		return false
+	}
+	s.fontScale = fontScale
+	return true
+}
+
+// FontScale returns the factor for pixel sizes of fonts.
+func (s *scaling) FontScale() float32 {
+	return s.fontScale
+}
@@ -0,0 +1,332 @@
+package example
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/AllenDang/cimgui-go"
+	"github.com/ptxmac/cimgui-go-examples/internal/callbacks"
+	"github.com/ptxmac/cimgui-go-examples/internal/demo"
+	"github.com/ptxmac/cimgui-go-examples/internal/events"
+	"github.com/ptxmac/cimgui-go-examples/internal/filedialog"
+	"github.com/ptxmac/cimgui-go-examples/internal/tasks"
+	"github.com/ptxmac/cimgui-go-examples/internal/textures"
+)
+
+const (
+	attentionDelay    = 3 * time.Second
+	countStepDuration = 100 * time.Millisecond
+
+	saveChangesPopupID = "Save changes?"
+
+	mainWindowTitle     = "Hello, world!"
+	anotherWindowTitle  = "Another window"
+	imageWindowTitle    = "Image window"
+	callbackWindowTitle = "Callback window"
+)
+
+// showcasePanels is the state of the showcase that persists between sessions.
+type showcasePanels struct {
+	ShowDemoWindow     bool `json:"showDemoWindow"`
+	ShowGoDemoWindow   bool `json:"showGoDemoWindow"`
+	ShowAnotherWindow  bool `json:"showAnotherWindow"`
+	ShowImageWindow    bool `json:"showImageWindow"`
+	ShowCallbackWindow bool `json:"showCallbackWindow"`
+}
+
+// showcase is the default app. It shows some basic features of ImGui, as well as exposing the standard demo window.
+type showcase struct {
+	showcasePanels
+	f        float32
+	counter  int
+	fontSize float32
+
+	droppedFiles     []string
+	missedDrops      int
+	backgroundClicks int
+
+	taskResult string
+
+	baseTitle    string
+	modified     bool
+	confirmClose bool
+
+	screenshot    *textures.Texture
+	screenshotErr error
+	loaded        bool
+
+	callbackCalls int
+	callbackClip  imgui.Vec4
+}
+
+func newShowcase() *showcase {
+	return &showcase{}
+}
+
+// BuildLayout docks the main window to the left and the other windows to the right.
+// The central node stays empty for the demo windows.
+func (app *showcase) BuildLayout(dockspace imgui.ID) {
+	var left, right, center imgui.ID
+	imgui.InternalDockBuilderSplitNode(dockspace, imgui.DirLeft, 0.3, &left, &center)
+	imgui.InternalDockBuilderSplitNode(center, imgui.DirRight, 0.4, &right, &center)
+	imgui.InternalDockBuilderDockWindow(mainWindowTitle, left)
+	imgui.InternalDockBuilderDockWindow(anotherWindowTitle, right)
+	imgui.InternalDockBuilderDockWindow(imageWindowTitle, right)
+	imgui.InternalDockBuilderDockWindow(callbackWindowTitle, right)
+}
+
+// FilterEvent counts the clicks that imgui does not use, as they hit no window.
+// They are still forwarded, as imgui clears the window focus with them.
+func (app *showcase) FilterEvent(host *Host, event events.Event) bool {
+	button, isButton := event.(events.MouseButton)
+	if isButton && (button.Action == events.ActionPress) && !imgui.CurrentIO().WantCaptureMouse() {
+		app.backgroundClicks++
+	}
+	return true
+}
+
+// imageFilters offer to show images only, or all files.
+var imageFilters = []filedialog.Filter{
+	{Name: "Images", Extensions: []string{".png", ".jpg", ".jpeg"}},
+	{Name: "All files"},
+}
+
+func (app *showcase) openFiles(host *Host) {
+	dialog, fsys := host.FileDialog()
+	config := filedialog.Config{Mode: filedialog.ModeOpen, Title: "Open images", Filters: imageFilters, Multiple: true}
+	dialog.Open(config, func(result filedialog.Result) {
+		if !result.OK {
+			return
+		}
+		app.droppedFiles = nil
+		for _, name := range result.Paths {
+			app.droppedFiles = append(app.droppedFiles, fsys.Path(name))
+		}
+	})
+}
+
+// saveAs only pretends to save the document, under the chosen name.
+func (app *showcase) saveAs(host *Host) {
+	dialog, fsys := host.FileDialog()
+	config := filedialog.Config{Mode: filedialog.ModeSave, Filename: "Untitled.png", Filters: imageFilters}
+	dialog.Open(config, func(result filedialog.Result) {
+		if !result.OK {
+			return
+		}
+		app.droppedFiles = []string{fsys.Path(result.Paths[0])}
+		app.modified = false
+	})
+}
+
+// countSlowly stands in for a long operation, such as scanning files.
+func countSlowly(ctx context.Context, task *tasks.Task) error {
+	const steps = 50
+	for step := 1; step <= steps; step++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(countStepDuration):
+		}
+		task.SetStatus(fmt.Sprintf("%d of %d", step, steps))
+		task.SetProgress(float32(step) / steps)
+	}
+	return nil
+}
+
+// countCallback is a draw callback that draws nothing itself. It records its calls and the clip rectangle
+// of its command, which shows that the renderer reaches it.
+func countCallback(list imgui.DrawList, command imgui.DrawCmd, data any) {
+	app := data.(*showcase)
+	app.callbackCalls++
+	app.callbackClip = command.ClipRect()
+}
+
+// resetCounter asks the user whether to reset the counter. It runs on a goroutine of its own,
+// so the counter is changed on the thread of the frame loop.
+func (app *showcase) resetCounter(host *Host) {
+	result := <-host.Dialogs().Confirm(context.Background(), "Reset the counter to zero?")
+	if result.OK {
+		host.Dispatcher().Post(func() { app.counter = 0 })
+	}
+}
+
+// CloseRequested asks whether to save the changes before the program ends, if there are any.
+func (app *showcase) CloseRequested(host *Host) bool {
+	if !app.modified {
+		return true
+	}
+	app.confirmClose = true
+	return false
+}
+
+// FilesDropped counts the drops outside of the drop target.
+func (app *showcase) FilesDropped(host *Host, drop events.Drop) {
+	app.missedDrops++
+}
+
+func (app *showcase) Frame(host *Host) {
+	if !app.loaded {
+		app.loaded = true
+		app.fontSize = host.FontSize()
+		app.baseTitle = host.Title()
+		store, _ := host.Settings()
+		_ = store.Register("showcase", &app.showcasePanels) // Invalid settings keep the defaults
+		cache, _ := host.Textures()
+		app.screenshot, app.screenshotErr = cache.Load("screenshot.png")
+	}
+	// The title shows the open document, the last dropped file, and a marker for unsaved changes.
+	document := "Untitled"
+	if len(app.droppedFiles) > 0 {
+		document = filepath.Base(app.droppedFiles[0])
+	}
+	if app.modified {
+		document += " *"
+	}
+	host.SetTitle(document + " - " + app.baseTitle)
+	// 1. Show a simple window.
+	{
+		fontSet, fontErr := host.Fonts()
+		clearColor := host.ClearColor()
+
+		imgui.Begin(mainWindowTitle)
+		imgui.Text(fontSet.Use("ภาษาไทย测试조선말"))                                      // Glyphs of these are added to the atlas on first use
+		imgui.Text("Hello, world!")                                                  // Display some text
+		imgui.SliderFloatV("float", &app.f, 0.0, 1.0, "%.3f", imgui.SliderFlagsNone) // Edit 1 float using a slider from 0.0f to 1.0f
+
+		if imgui.ColorEdit3("clear color", &clearColor) { // Edit 3 floats representing a color
+			host.SetClearColor(clearColor)
+		}
+		imgui.SliderFloatV("font size", &app.fontSize, 8, 32, "%.0f", imgui.SliderFlagsNone)
+		if imgui.IsItemDeactivatedAfterEdit() { // Rebuild the font atlas only once the slider is released
+			host.SetFontSize(app.fontSize)
+		}
+		if fontErr != nil {
+			imgui.Text(fmt.Sprintf("Failed to load fonts: %v", fontErr))
+		}
+
+		imgui.Checkbox("Demo Window", &app.ShowDemoWindow) // Edit bools storing our window open/close state
+		imgui.Checkbox("Go Demo Window", &app.ShowGoDemoWindow)
+		imgui.Checkbox("Another Window", &app.ShowAnotherWindow)
+		imgui.Checkbox("Image Window", &app.ShowImageWindow)
+		imgui.Checkbox("Callback Window", &app.ShowCallbackWindow)
+
+		if imgui.Button("Button") { // Buttons return true when clicked (most widgets return true when edited/activated)
+			app.counter++
+		}
+		imgui.SameLine()
+		imgui.Text(fmt.Sprintf("counter = %d", app.counter))
+		imgui.SameLine()
+		if imgui.Button("Reset...") {
+			// Dialogs may be asked for from any goroutine; the answer arrives on a channel.
+			go app.resetCounter(host)
+		}
+
+		imgui.ButtonV("Drop files here", imgui.Vec2{X: -1}) // Any item can be a target for files dropped onto the window
+		if paths, dropped := host.Drops().AcceptItem(); dropped {
+			app.droppedFiles = paths
+		}
+		if imgui.Button("Open...") { // Opened files are listed like dropped ones
+			app.openFiles(host)
+		}
+		imgui.SameLine()
+		if imgui.Button("Save as...") {
+			app.saveAs(host)
+		}
+		for _, path := range app.droppedFiles {
+			imgui.Bullet()
+			imgui.TextUnformatted(path)
+		}
+		if app.missedDrops > 0 {
+			imgui.Text(fmt.Sprintf("%d drops missed the target", app.missedDrops))
+		}
+		imgui.Text(fmt.Sprintf("%d clicks on the background", app.backgroundClicks))
+		imgui.Checkbox("Unsaved changes", &app.modified)
+		if imgui.Button("Request attention in 3 seconds") { // Switch to another window to see the effect
+			// The timer runs on a goroutine of its own; the window must only be changed from the frame loop.
+			dispatcher := host.Dispatcher()
+			time.AfterFunc(attentionDelay, func() { dispatcher.Post(host.RequestAttention) })
+		}
+
+		if imgui.Button("Start background task") { // The progress is shown in the status bar
+			host.Tasks().Start("Counting", countSlowly, func(task *tasks.Task) {
+				app.taskResult = "Counting finished"
+				if err := task.Err(); err != nil {
+					app.taskResult = fmt.Sprintf("Counting stopped: %v", err)
+				}
+			})
+		}
+		if app.taskResult != "" {
+			imgui.SameLine()
+			imgui.TextUnformatted(app.taskResult)
+		}
+
+		profilerVisible := host.Profiler().Visible()
+		if imgui.Checkbox("Profiler (F12)", &profilerVisible) {
+			host.Profiler().SetVisible(profilerVisible)
+		}
+		skipFrames := host.Redraw().Enabled()
+		if imgui.Checkbox("Skip unchanged frames", &skipFrames) {
+			host.Redraw().SetEnabled(skipFrames)
+		}
+		imgui.Text(fmt.Sprintf("Application average %.3f ms/frame (%.1f FPS)",
+			millisPerSecond/imgui.CurrentIO().Framerate(), imgui.CurrentIO().Framerate()))
+		imgui.End()
+	}
+
+	// 2. Show another simple window. In most cases you will use an explicit Begin/End pair to name your windows.
+	if app.ShowAnotherWindow {
+		// Pass a pointer to our bool variable (the window will have a closing button that will clear the bool when clicked)
+		imgui.BeginV(anotherWindowTitle, &app.ShowAnotherWindow, 0)
+		imgui.Text("Hello from another window!")
+		if imgui.Button("Close Me") {
+			app.ShowAnotherWindow = false
+		}
+		imgui.End()
+	}
+
+	// 3. Show an image from the embedded assets, scaled to the window while keeping its aspect ratio.
+	if app.ShowImageWindow {
+		imgui.BeginV(imageWindowTitle, &app.ShowImageWindow, 0)
+		if _, reloadErr := host.Textures(); reloadErr != nil { // The image keeps its previous content
+			imgui.Text(fmt.Sprintf("Failed to reload image: %v", reloadErr))
+			imgui.SameLine()
+			if imgui.Button("Dismiss") {
+				host.DismissTextureError()
+			}
+		}
+		if app.screenshotErr != nil {
+			imgui.Text(fmt.Sprintf("Failed to load image: %v", app.screenshotErr))
+		} else {
+			textures.Image(app.screenshot, imgui.Vec2{})
+		}
+		imgui.End()
+	}
+
+	// 4. Show a window with a draw callback, which the renderer calls while it renders the window.
+	// Frames with callbacks are never skipped, as the detector cannot tell what a callback draws.
+	if app.ShowCallbackWindow {
+		imgui.BeginV(callbackWindowTitle, &app.ShowCallbackWindow, 0)
+		imgui.Text(fmt.Sprintf("The draw callback ran %d times", app.callbackCalls))
+		clip := app.callbackClip
+		imgui.Text(fmt.Sprintf("Clip rectangle of the last call: (%.0f, %.0f) - (%.0f, %.0f)", clip.X, clip.Y, clip.Z, clip.W))
+		callbacks.Add(imgui.WindowDrawList(), countCallback, app)
+		imgui.End()
+	}
+
+	// 5. Show the ImGui demo window. Most of the sample code is in cimgui.ShowDemoWindow().
+	// Read its code to learn more about Dear ImGui!
+	if app.ShowDemoWindow {
+		// Normally user code doesn't need/want to call this because positions are saved in .ini file anyway.
+		// Here we just want to make the demo initial state a bit more friendly!
+		const demoX = 650
+		const demoY = 20
+		imgui.SetNextWindowPosV(imgui.Vec2{X: demoX, Y: demoY}, imgui.CondFirstUseEver, imgui.Vec2{})
+
+		imgui.ShowDemoWindowV(&app.ShowDemoWindow)
+	}
+	if app.ShowGoDemoWindow {
+		demo.Show(&app.ShowGoDemoWindow)
+	}
+}
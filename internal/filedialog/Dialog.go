@@ -0,0 +1,658 @@
+package filedialog
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/AllenDang/cimgui-go"
+)
+
+// Mode tells whether a dialog opens or saves files.
+type Mode int
+
+// This is a list of Mode constants.
+const (
+	ModeOpen Mode = iota
+	ModeSave
+)
+
+// Config describes a dialog to show.
+type Config struct {
+	Mode Mode
+	// Title is the title of the dialog. It defaults to "Open" or "Save".
+	Title string
+	// Dir is the initial folder. It defaults to the most recent location, the home folder, or the root of the file system.
+	Dir string
+	// Filename is the initial name of the file to save.
+	Filename string
+	// Filters are offered to limit the shown files, the first one is selected initially.
+	// Without filters, all files are shown.
+	Filters []Filter
+	// Multiple allows to select more than one file to open.
+	Multiple bool
+}
+
+// Result is the outcome of a dialog.
+type Result struct {
+	// OK is true if the user confirmed the dialog, false if the user canceled it.
+	OK bool
+	// Paths are the chosen files, in the order of the listing. Saving yields a single path,
+	// which does not need to exist. The extension of the selected filter is added to a name without one.
+	Paths []string
+}
+
+// State is the part of a dialog that persists between sessions.
+type State struct {
+	// Recent holds the folders of the last confirmed dialogs, the most recent first.
+	Recent []string `json:"recent"`
+	// ShowHidden tells whether files and folders with a leading dot are shown.
+	ShowHidden bool `json:"showHidden"`
+}
+
+const (
+	idSuffix            = "##filedialog"
+	overwritePopupID    = "Replace file?"
+	rootLabel           = "Root"
+	homeLabel           = "Home"
+	maxRecent           = 10
+	dateLayout          = "2006-01-02 15:04"
+	placesWidth         = 10 // in multiples of the font size
+	initialWidth        = 50
+	initialHeight       = 30
+	sizeColumnWidth     = 6
+	modifiedColumnWidth = 9
+)
+
+// Dialog browses a file system to choose files to open, or a file to save.
+// A Dialog must only be used from the thread that runs the frame loop.
+type Dialog struct {
+	fsys  fs.FS
+	home  string
+	state State
+
+	config  Config
+	done    func(Result)
+	opening bool
+	result  *Result
+
+	dir      string
+	entries  []entry
+	filter   int
+	selected map[string]bool
+	// cursor is the name of the entry that keyboard navigation starts from, anchor the start of a range selection.
+	cursor         string
+	anchor         string
+	scrollToCursor bool
+	sortColumn     int
+	sortAscending  bool
+
+	filename       string
+	creatingFolder bool
+	focusFolder    bool
+	newFolder      string
+	openOverwrite  bool
+	overwritePath  string
+	err            error
+}
+
+// New returns a dialog that browses given file system. Folders can be created if the file system implements MkdirFS.
+// The home folder is offered next to the recent locations; it may be empty if the file system has none.
+func New(fsys fs.FS, home string) *Dialog {
+	return &Dialog{
+		fsys:          fsys,
+		home:          home,
+		selected:      make(map[string]bool),
+		sortAscending: true,
+	}
+}
+
+// State returns the state that persists between sessions, for example to register it with a settings store.
+func (dialog *Dialog) State() *State {
+	return &dialog.state
+}
+
+// Visible returns true while the dialog waits for the user.
+func (dialog *Dialog) Visible() bool {
+	return dialog.done != nil
+}
+
+// Open shows the dialog, starting with the next frame. done is called on the thread of the frame loop,
+// once the user confirmed or canceled the dialog. A dialog that is still visible is canceled first.
+func (dialog *Dialog) Open(config Config, done func(Result)) {
+	if dialog.done != nil {
+		dialog.finish(Result{})
+	}
+	dialog.config = config
+	dialog.done = done
+	dialog.opening = true
+	dialog.filter = 0
+	dialog.filename = config.Filename
+	dialog.creatingFolder = false
+	dialog.err = nil
+
+	dir := config.Dir
+	if (dir == "") && (len(dialog.state.Recent) > 0) {
+		dir = dialog.state.Recent[0]
+	}
+	if dir == "" {
+		dir = dialog.home
+	}
+	if (dir == "") || (dialog.navigate(dir) != nil) {
+		dialog.err = dialog.navigate(".")
+	}
+}
+
+// Frame shows the dialog while it is visible. It must be called between imgui.NewFrame() and imgui.Render(),
+// outside of any window.
+func (dialog *Dialog) Frame() {
+	if dialog.done == nil {
+		return
+	}
+	id := dialog.title() + idSuffix
+	if dialog.opening {
+		dialog.opening = false
+		imgui.OpenPopupStr(id)
+	}
+	fontSize := imgui.FontSize()
+	imgui.SetNextWindowSizeV(imgui.Vec2{X: fontSize * initialWidth, Y: fontSize * initialHeight}, imgui.CondAppearing)
+	if !imgui.BeginPopupModalV(id, nil, 0) {
+		// The popup was closed by imgui itself, for example because another popup replaced it.
+		dialog.finish(Result{})
+		return
+	}
+
+	dialog.breadcrumbs()
+	footerRows := 2
+	if dialog.config.Mode == ModeSave {
+		footerRows++
+	}
+	if dialog.err != nil {
+		footerRows++
+	}
+	footerHeight := imgui.FrameHeightWithSpacing() * float32(footerRows)
+	if imgui.BeginChildStrV("##places", imgui.Vec2{X: fontSize * placesWidth, Y: -footerHeight}, true, 0) {
+		dialog.places()
+	}
+	imgui.EndChild()
+	imgui.SameLine()
+	dialog.listing(imgui.Vec2{Y: -footerHeight})
+	dialog.footer()
+	dialog.overwritePopup()
+	dialog.handleKeys()
+
+	if dialog.result != nil {
+		imgui.CloseCurrentPopup()
+		dialog.finish(*dialog.result)
+	}
+	imgui.EndPopup()
+}
+
+func (dialog *Dialog) title() string {
+	if dialog.config.Title != "" {
+		return dialog.config.Title
+	}
+	if dialog.config.Mode == ModeSave {
+		return "Save"
+	}
+	return "Open"
+}
+
+func (dialog *Dialog) finish(result Result) {
+	if result.OK {
+		dialog.addRecent(dialog.dir)
+	}
+	done := dialog.done
+	dialog.done = nil
+	dialog.result = nil
+	done(result)
+}
+
+func (dialog *Dialog) answer(result Result) {
+	dialog.result = &result
+}
+
+func (dialog *Dialog) addRecent(dir string) {
+	recent := []string{dir}
+	for _, candidate := range dialog.state.Recent {
+		if (candidate != dir) && (len(recent) < maxRecent) {
+			recent = append(recent, candidate)
+		}
+	}
+	dialog.state.Recent = recent
+}
+
+func (dialog *Dialog) currentFilter() Filter {
+	if dialog.filter >= len(dialog.config.Filters) {
+		return Filter{}
+	}
+	return dialog.config.Filters[dialog.filter]
+}
+
+// navigate lists the folder. The current folder is kept if the new one can not be read.
+func (dialog *Dialog) navigate(dir string) error {
+	dir = path.Clean(dir)
+	entries, err := readDir(dialog.fsys, dir, dialog.currentFilter(), dialog.state.ShowHidden)
+	if err != nil {
+		return err
+	}
+	sortEntries(entries, dialog.sortColumn, dialog.sortAscending)
+	dialog.dir = dir
+	dialog.entries = entries
+	dialog.selected = make(map[string]bool)
+	dialog.cursor = ""
+	dialog.anchor = ""
+	dialog.err = nil
+	return nil
+}
+
+// refresh lists the current folder again, keeping the selection of the entries that are still listed.
+func (dialog *Dialog) refresh() {
+	selected, cursor := dialog.selected, dialog.cursor
+	dialog.err = dialog.navigate(dialog.dir)
+	for _, e := range dialog.entries {
+		if selected[e.name] {
+			dialog.selected[e.name] = true
+		}
+		if e.name == cursor {
+			dialog.cursor, dialog.anchor = cursor, cursor
+		}
+	}
+}
+
+func (dialog *Dialog) breadcrumbs() {
+	imgui.BeginDisabledV(dialog.dir == ".")
+	if imgui.ArrowButton("##up", imgui.DirUp) {
+		dialog.err = dialog.navigate(path.Dir(dialog.dir))
+	}
+	imgui.EndDisabled()
+	imgui.SameLine()
+	if imgui.Button(rootLabel) {
+		dialog.err = dialog.navigate(".")
+	}
+	if dialog.dir == "." {
+		return
+	}
+	parts := strings.Split(dialog.dir, "/")
+	for i, part := range parts {
+		imgui.SameLine()
+		imgui.TextDisabled("/")
+		imgui.SameLine()
+		imgui.PushIDInt(int32(i))
+		if imgui.Button(part) {
+			dialog.err = dialog.navigate(strings.Join(parts[:i+1], "/"))
+		}
+		imgui.PopID()
+	}
+}
+
+func (dialog *Dialog) places() {
+	if (dialog.home != "") && (dialog.home != ".") {
+		if imgui.SelectableBoolV(homeLabel, dialog.dir == dialog.home, 0, imgui.Vec2{}) {
+			dialog.err = dialog.navigate(dialog.home)
+		}
+	}
+	if imgui.SelectableBoolV(rootLabel, dialog.dir == ".", 0, imgui.Vec2{}) {
+		dialog.err = dialog.navigate(".")
+	}
+	imgui.Separator()
+	imgui.TextDisabled("Recent")
+	for i, dir := range dialog.state.Recent {
+		label := path.Base(dir)
+		if dir == "." {
+			label = rootLabel
+		}
+		imgui.PushIDInt(int32(i))
+		if imgui.SelectableBoolV(label, dir == dialog.dir, 0, imgui.Vec2{}) {
+			dialog.err = dialog.navigate(dir)
+		}
+		if imgui.IsItemHovered() {
+			imgui.SetTooltip(dir)
+		}
+		imgui.PopID()
+	}
+}
+
+func (dialog *Dialog) listing(size imgui.Vec2) {
+	flags := imgui.TableFlags(imgui.TableFlagsSortable | imgui.TableFlagsRowBg | imgui.TableFlagsScrollY |
+		imgui.TableFlagsResizable | imgui.TableFlagsBordersOuter)
+	if !imgui.BeginTableV("##entries", columnCount, flags, size, 0) {
+		return
+	}
+	fontSize := imgui.FontSize()
+	imgui.TableSetupScrollFreeze(0, 1)
+	imgui.TableSetupColumnV("Name", imgui.TableColumnFlags(imgui.TableColumnFlagsDefaultSort|imgui.TableColumnFlagsWidthStretch), 0, 0)
+	imgui.TableSetupColumnV("Size", imgui.TableColumnFlagsWidthFixed, fontSize*sizeColumnWidth, 0)
+	imgui.TableSetupColumnV("Modified", imgui.TableColumnFlagsWidthFixed, fontSize*modifiedColumnWidth, 0)
+	imgui.TableHeadersRow()
+	if specs := imgui.TableGetSortSpecs(); (specs != 0) && specs.SpecsDirty() {
+		column := specs.Specs()
+		dialog.sortColumn = column.ColumnIndex()
+		dialog.sortAscending = column.SortDirection() == imgui.SortDirectionAscending
+		sortEntries(dialog.entries, dialog.sortColumn, dialog.sortAscending)
+		specs.SetSpecsDirty(false)
+	}
+
+	rowFlags := imgui.SelectableFlags(imgui.SelectableFlagsSpanAllColumns | imgui.SelectableFlagsAllowDoubleClick)
+	for i, e := range dialog.entries {
+		imgui.TableNextRow()
+		imgui.TableNextColumn()
+		label := e.name
+		if e.dir {
+			label += "/"
+		}
+		imgui.PushIDInt(int32(i))
+		if imgui.SelectableBoolV(label, dialog.selected[e.name], rowFlags, imgui.Vec2{}) {
+			io := imgui.CurrentIO()
+			dialog.selectEntry(e, io.KeyCtrl(), io.KeyShift())
+			if imgui.IsMouseDoubleClicked(imgui.MouseButtonLeft) {
+				dialog.activate(e)
+			}
+		}
+		imgui.PopID()
+		if dialog.scrollToCursor && (e.name == dialog.cursor) {
+			dialog.scrollToCursor = false
+			imgui.SetScrollHereYV(0.5)
+		}
+		imgui.TableNextColumn()
+		if !e.dir {
+			imgui.TextUnformatted(formatSize(e.size))
+		}
+		imgui.TableNextColumn()
+		imgui.TextUnformatted(e.modified.Format(dateLayout))
+	}
+	imgui.EndTable()
+}
+
+// selectEntry changes the selection as a click on the entry does. With multiple selection,
+// toggle adds or removes the entry, and extend selects the range from the anchor.
+func (dialog *Dialog) selectEntry(e entry, toggle, extend bool) {
+	multiple := (dialog.config.Mode == ModeOpen) && dialog.config.Multiple
+	// The anchor may not be listed, for example a new hidden folder; a range then falls back to a single selection.
+	from, to := dialog.indexOf(dialog.anchor), dialog.indexOf(e.name)
+	switch {
+	case multiple && toggle:
+		if dialog.selected[e.name] {
+			delete(dialog.selected, e.name)
+		} else {
+			dialog.selected[e.name] = true
+		}
+		dialog.anchor = e.name
+	case multiple && extend && (from >= 0) && (to >= 0):
+		if from > to {
+			from, to = to, from
+		}
+		dialog.selected = make(map[string]bool)
+		for i := from; i <= to; i++ {
+			dialog.selected[dialog.entries[i].name] = true
+		}
+	default:
+		dialog.selected = map[string]bool{e.name: true}
+		dialog.anchor = e.name
+	}
+	dialog.cursor = e.name
+	if (dialog.config.Mode == ModeSave) && !e.dir {
+		dialog.filename = e.name
+	}
+}
+
+func (dialog *Dialog) indexOf(name string) int {
+	for i, e := range dialog.entries {
+		if e.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// activate enters a folder, or confirms the dialog for a file, as a double click does.
+func (dialog *Dialog) activate(e entry) {
+	if e.dir {
+		dialog.err = dialog.navigate(path.Join(dialog.dir, e.name))
+		return
+	}
+	dialog.confirm()
+}
+
+func (dialog *Dialog) footer() {
+	if dialog.config.Mode == ModeSave {
+		imgui.PushItemWidth(-1)
+		if imgui.InputTextWithHint("##filename", "File name", &dialog.filename, imgui.InputTextFlagsEnterReturnsTrue, nil) {
+			dialog.confirm()
+		}
+		imgui.PopItemWidth()
+	}
+
+	if len(dialog.config.Filters) > 0 {
+		imgui.PushItemWidth(imgui.FontSize() * placesWidth * 2)
+		if imgui.BeginComboV("##filter", dialog.currentFilter().String(), 0) {
+			for i, filter := range dialog.config.Filters {
+				imgui.PushIDInt(int32(i))
+				if imgui.SelectableBoolV(filter.String(), i == dialog.filter, 0, imgui.Vec2{}) {
+					dialog.filter = i
+					dialog.refresh()
+				}
+				imgui.PopID()
+			}
+			imgui.EndCombo()
+		}
+		imgui.PopItemWidth()
+		imgui.SameLine()
+	}
+	if imgui.Checkbox("Hidden files", &dialog.state.ShowHidden) {
+		dialog.refresh()
+	}
+	if _, writable := dialog.fsys.(MkdirFS); writable {
+		imgui.SameLine()
+		dialog.folderCreation()
+	}
+
+	if dialog.err != nil {
+		imgui.PushStyleColorVec4(imgui.ColText, errorColor)
+		imgui.TextUnformatted(dialog.err.Error())
+		imgui.PopStyleColor()
+	}
+
+	label := "Open"
+	if dialog.config.Mode == ModeSave {
+		label = "Save"
+	}
+	imgui.BeginDisabledV(!dialog.canConfirm())
+	if imgui.Button(label) {
+		dialog.confirm()
+	}
+	imgui.EndDisabled()
+	imgui.SameLine()
+	if imgui.Button("Cancel") {
+		dialog.answer(Result{})
+	}
+}
+
+var errorColor = imgui.Vec4{X: 1, Y: 0.4, Z: 0.4, W: 1}
+
+func (dialog *Dialog) folderCreation() {
+	if !dialog.creatingFolder {
+		if imgui.Button("New folder") {
+			dialog.creatingFolder = true
+			dialog.focusFolder = true
+			dialog.newFolder = ""
+		}
+		return
+	}
+	if dialog.focusFolder {
+		dialog.focusFolder = false
+		imgui.SetKeyboardFocusHere()
+	}
+	imgui.PushItemWidth(imgui.FontSize() * placesWidth)
+	created := imgui.InputTextWithHint("##folder", "Folder name", &dialog.newFolder, imgui.InputTextFlagsEnterReturnsTrue, nil)
+	imgui.PopItemWidth()
+	imgui.SameLine()
+	created = imgui.Button("Create") || created
+	imgui.SameLine()
+	if imgui.Button("Discard") {
+		dialog.creatingFolder = false
+	}
+	if created {
+		dialog.createFolder()
+	}
+}
+
+func (dialog *Dialog) createFolder() {
+	mkdirFS, writable := dialog.fsys.(MkdirFS)
+	if !writable {
+		dialog.err = ErrReadOnly
+		return
+	}
+	name := strings.TrimSpace(dialog.newFolder)
+	if !validName(name) {
+		dialog.err = ErrInvalidName
+		return
+	}
+	err := mkdirFS.Mkdir(path.Join(dialog.dir, name))
+	if err != nil {
+		dialog.err = err
+		return
+	}
+	dialog.creatingFolder = false
+	dialog.refresh()
+	if dialog.indexOf(name) < 0 {
+		return // Hidden folders are not listed unless hidden files are shown
+	}
+	dialog.selected = map[string]bool{name: true}
+	dialog.cursor, dialog.anchor = name, name
+	dialog.scrollToCursor = true
+}
+
+// validName returns true if the name can be used for a single file or folder.
+func validName(name string) bool {
+	return (name != "") && (name != ".") && (name != "..") && !strings.ContainsAny(name, `/\`)
+}
+
+func (dialog *Dialog) canConfirm() bool {
+	if dialog.config.Mode == ModeSave {
+		return strings.TrimSpace(dialog.filename) != ""
+	}
+	return len(dialog.selected) > 0
+}
+
+// confirm finishes the dialog with the chosen files. If only a folder is chosen, the folder is entered instead.
+func (dialog *Dialog) confirm() {
+	if dialog.config.Mode == ModeSave {
+		dialog.confirmSave()
+		return
+	}
+	var paths []string
+	var folder string
+	for _, e := range dialog.entries {
+		if !dialog.selected[e.name] {
+			continue
+		}
+		if e.dir {
+			folder = e.name
+		} else {
+			paths = append(paths, path.Join(dialog.dir, e.name))
+		}
+	}
+	switch {
+	case len(paths) > 0:
+		dialog.answer(Result{OK: true, Paths: paths})
+	case folder != "":
+		dialog.err = dialog.navigate(path.Join(dialog.dir, folder))
+	}
+}
+
+func (dialog *Dialog) confirmSave() {
+	name := strings.TrimSpace(dialog.filename)
+	if !validName(name) {
+		dialog.err = ErrInvalidName
+		return
+	}
+	if filter := dialog.currentFilter(); (path.Ext(name) == "") && (len(filter.Extensions) > 0) {
+		name += filter.Extensions[0]
+	}
+	target := path.Join(dialog.dir, name)
+	info, err := fs.Stat(dialog.fsys, target)
+	switch {
+	case (err == nil) && info.IsDir():
+		dialog.filename = ""
+		dialog.err = dialog.navigate(target)
+	case err == nil:
+		dialog.overwritePath = target
+		dialog.openOverwrite = true
+	default:
+		dialog.answer(Result{OK: true, Paths: []string{target}})
+	}
+}
+
+func (dialog *Dialog) overwritePopup() {
+	if dialog.openOverwrite {
+		dialog.openOverwrite = false
+		imgui.OpenPopupStr(overwritePopupID)
+	}
+	if !imgui.BeginPopupModalV(overwritePopupID, nil, imgui.WindowFlagsAlwaysAutoResize) {
+		return
+	}
+	imgui.TextUnformatted(path.Base(dialog.overwritePath) + " exists already. Do you want to replace it?")
+	if imgui.Button("Replace") {
+		imgui.CloseCurrentPopup()
+		dialog.answer(Result{OK: true, Paths: []string{dialog.overwritePath}})
+	}
+	imgui.SameLine()
+	if imgui.Button("Cancel") || imgui.IsKeyPressedBool(imgui.KeyEscape) {
+		imgui.CloseCurrentPopup()
+	}
+	imgui.EndPopup()
+}
+
+// handleKeys moves the cursor with the arrow keys, enters folders or confirms with Enter,
+// goes to the parent folder with Backspace, and cancels with Escape. Keys are ignored while text is entered.
+func (dialog *Dialog) handleKeys() {
+	if !imgui.IsWindowFocusedV(imgui.FocusedFlagsRootAndChildWindows) || imgui.CurrentIO().WantTextInput() {
+		return
+	}
+	switch {
+	case imgui.IsKeyPressedBool(imgui.KeyEscape):
+		dialog.answer(Result{})
+	case imgui.IsKeyPressedBool(imgui.KeyBackspace) && (dialog.dir != "."):
+		dialog.err = dialog.navigate(path.Dir(dialog.dir))
+	case imgui.IsKeyPressedBool(imgui.KeyEnter) || imgui.IsKeyPressedBool(imgui.KeyKeypadEnter):
+		if index := dialog.indexOf(dialog.cursor); index >= 0 {
+			dialog.activate(dialog.entries[index])
+		}
+	case imgui.IsKeyPressedBool(imgui.KeyDownArrow):
+		dialog.moveCursor(1)
+	case imgui.IsKeyPressedBool(imgui.KeyUpArrow):
+		dialog.moveCursor(-1)
+	}
+}
+
+func (dialog *Dialog) moveCursor(delta int) {
+	if len(dialog.entries) == 0 {
+		return
+	}
+	index := dialog.indexOf(dialog.cursor) + delta
+	if index < 0 {
+		index = 0
+	} else if index >= len(dialog.entries) {
+		index = len(dialog.entries) - 1
+	}
+	dialog.selectEntry(dialog.entries[index], false, imgui.CurrentIO().KeyShift())
+	dialog.scrollToCursor = true
+}
+
+// formatSize returns the size in bytes with a binary unit prefix.
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value := float64(size) / unit
+	prefixes := "KMGTPE"
+	index := 0
+	for (value >= unit) && (index < len(prefixes)-1) {
+		value /= unit
+		index++
+	}
+	return fmt.Sprintf("%.1f %ciB", value, prefixes[index])
+}
@@ -0,0 +1,108 @@
+package filedialog
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+)
+
+func TestDialogOpensHomeFolder(t *testing.T) {
+	dialog := New(testFS(), "docs")
+	dialog.Open(Config{Mode: ModeOpen}, func(Result) {})
+
+	if dialog.err != nil {
+		t.Fatalf("Open failed: %v", dialog.err)
+	}
+	if dialog.dir != "docs" {
+		t.Errorf("dialog shows %q, expected home folder", dialog.dir)
+	}
+	assertNames(t, dialog.entries, "sub", "zeta", "A.PNG", "b.png", "c.txt")
+}
+
+func TestDialogOpensRootForMissingHome(t *testing.T) {
+	dialog := New(testFS(), "missing")
+	dialog.Open(Config{Mode: ModeOpen}, func(Result) {})
+
+	if dialog.dir != "." {
+		t.Errorf("dialog shows %q, expected root", dialog.dir)
+	}
+}
+
+func TestDialogCreateFolderFailsOnReadOnlyFileSystem(t *testing.T) {
+	dialog := New(testFS(), "docs")
+	dialog.Open(Config{Mode: ModeSave}, func(Result) {})
+	dialog.newFolder = "new"
+	dialog.createFolder()
+
+	if !errors.Is(dialog.err, ErrReadOnly) {
+		t.Errorf("createFolder returned %v, expected ErrReadOnly", dialog.err)
+	}
+}
+
+func TestDialogCreateFolderRejectsInvalidNames(t *testing.T) {
+	dialog := New(NewDirFS(t.TempDir()), ".")
+	dialog.Open(Config{Mode: ModeSave}, func(Result) {})
+	for _, name := range []string{"", "  ", ".", "..", "a/b", `a\b`} {
+		dialog.err = nil
+		dialog.newFolder = name
+		dialog.createFolder()
+		if !errors.Is(dialog.err, ErrInvalidName) {
+			t.Errorf("createFolder(%q) returned %v, expected ErrInvalidName", name, dialog.err)
+		}
+	}
+}
+
+func TestDialogCreateFolderReportsErrorOfFileSystem(t *testing.T) {
+	dialog := New(NewDirFS(t.TempDir()), ".")
+	dialog.Open(Config{Mode: ModeSave}, func(Result) {})
+	dialog.newFolder = "twice"
+	dialog.createFolder()
+	dialog.newFolder = "twice"
+	dialog.createFolder()
+
+	if !errors.Is(dialog.err, fs.ErrExist) {
+		t.Errorf("createFolder returned %v, expected fs.ErrExist", dialog.err)
+	}
+}
+
+func TestDialogCreateFolderSelectsNewFolder(t *testing.T) {
+	dialog := New(NewDirFS(t.TempDir()), ".")
+	dialog.Open(Config{Mode: ModeSave}, func(Result) {})
+	dialog.creatingFolder = true
+	dialog.newFolder = " new "
+	dialog.createFolder()
+
+	if dialog.err != nil {
+		t.Fatalf("createFolder failed: %v", dialog.err)
+	}
+	if dialog.creatingFolder {
+		t.Error("dialog still asks for a folder name")
+	}
+	assertNames(t, dialog.entries, "new")
+	if !dialog.selected["new"] || (dialog.cursor != "new") {
+		t.Errorf("new folder not selected: %v, cursor %q", dialog.selected, dialog.cursor)
+	}
+}
+
+func TestDialogCreateFolderKeepsSelectionForUnlistedFolder(t *testing.T) {
+	dialog := New(NewDirFS(t.TempDir()), ".")
+	dialog.Open(Config{Mode: ModeOpen, Multiple: true}, func(Result) {})
+	for _, name := range []string{"a", ".cache"} {
+		dialog.newFolder = name
+		dialog.createFolder()
+		if dialog.err != nil {
+			t.Fatalf("createFolder(%q) failed: %v", name, dialog.err)
+		}
+	}
+
+	assertNames(t, dialog.entries, "a")
+	if dialog.selected[".cache"] || (dialog.cursor == ".cache") || (dialog.anchor == ".cache") {
+		t.Errorf("hidden folder selected: %v, cursor %q, anchor %q", dialog.selected, dialog.cursor, dialog.anchor)
+	}
+	// A range from an anchor that is not listed selects the clicked entry only.
+	dialog.anchor = ".cache"
+	dialog.selectEntry(dialog.entries[0], false, true)
+	if (len(dialog.selected) != 1) || !dialog.selected["a"] {
+		t.Errorf("shift-click selected %v, expected a", dialog.selected)
+	}
+}
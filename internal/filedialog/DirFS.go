@@ -0,0 +1,53 @@
+package filedialog
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MkdirFS is a file system in which the dialog can create folders.
+type MkdirFS interface {
+	fs.FS
+	// Mkdir creates the folder of given name. The parent folder must exist.
+	Mkdir(name string) error
+}
+
+// DirFS is the file system of a directory of the operating system, in which folders can be created.
+type DirFS struct {
+	fs.FS
+	root string
+}
+
+// NewDirFS returns the file system of the directory, such as "/" for the whole file system on Unix.
+func NewDirFS(root string) DirFS {
+	return DirFS{FS: os.DirFS(root), root: root}
+}
+
+// Mkdir creates the folder of given name.
+func (dir DirFS) Mkdir(name string) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrInvalid}
+	}
+	return os.Mkdir(dir.Path(name), 0o755)
+}
+
+// Path returns the path of the operating system for a name within the file system.
+func (dir DirFS) Path(name string) string {
+	return filepath.Join(dir.root, filepath.FromSlash(name))
+}
+
+// Name returns the name within the file system for a path of the operating system.
+// It returns false if the path is not within the root directory.
+func (dir DirFS) Name(path string) (string, bool) {
+	rel, err := filepath.Rel(dir.root, path)
+	if err != nil {
+		return "", false
+	}
+	name := filepath.ToSlash(rel)
+	if (name == "..") || strings.HasPrefix(name, "../") {
+		return "", false
+	}
+	return name, true
+}
@@ -0,0 +1,61 @@
+package filedialog
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDirFSMkdirCreatesFolder(t *testing.T) {
+	root := t.TempDir()
+	dir := NewDirFS(root)
+
+	if err := dir.Mkdir("new"); err != nil {
+		t.Fatalf("Mkdir failed: %v", err)
+	}
+	if info, err := os.Stat(filepath.Join(root, "new")); (err != nil) || !info.IsDir() {
+		t.Errorf("folder not created: %v", err)
+	}
+	if info, err := fs.Stat(dir, "new"); (err != nil) || !info.IsDir() {
+		t.Errorf("folder not visible in file system: %v", err)
+	}
+}
+
+func TestDirFSMkdirFails(t *testing.T) {
+	dir := NewDirFS(t.TempDir())
+	if err := dir.Mkdir("existing"); err != nil {
+		t.Fatalf("Mkdir failed: %v", err)
+	}
+	tt := []struct {
+		name     string
+		expected error
+	}{
+		{name: "existing", expected: fs.ErrExist},
+		{name: "missing/child", expected: fs.ErrNotExist},
+		{name: "../outside", expected: fs.ErrInvalid},
+		{name: "/absolute", expected: fs.ErrInvalid},
+	}
+	for _, tc := range tt {
+		if err := dir.Mkdir(tc.name); !errors.Is(err, tc.expected) {
+			t.Errorf("Mkdir(%q) returned %v, expected %v", tc.name, err, tc.expected)
+		}
+	}
+}
+
+func TestDirFSNameAndPath(t *testing.T) {
+	root := t.TempDir()
+	dir := NewDirFS(root)
+
+	path := dir.Path("a/b.txt")
+	if path != filepath.Join(root, "a", "b.txt") {
+		t.Errorf("unexpected path %q", path)
+	}
+	if name, within := dir.Name(path); !within || (name != "a/b.txt") {
+		t.Errorf("Name(%q) = %q, %v", path, name, within)
+	}
+	if _, within := dir.Name(filepath.Dir(root)); within {
+		t.Error("parent of root reported within the file system")
+	}
+}
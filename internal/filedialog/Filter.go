@@ -0,0 +1,40 @@
+package filedialog
+
+import (
+	"path"
+	"strings"
+)
+
+// Filter limits the shown files to those with one of the extensions.
+type Filter struct {
+	// Name describes the files, such as "Images".
+	Name string
+	// Extensions lists the extensions including the dot, such as ".png". Without extensions, all files match.
+	Extensions []string
+}
+
+// Matches returns true if the name has one of the extensions, ignoring the case.
+func (filter Filter) Matches(name string) bool {
+	if len(filter.Extensions) == 0 {
+		return true
+	}
+	ext := path.Ext(name)
+	for _, candidate := range filter.Extensions {
+		if strings.EqualFold(ext, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the name and the extensions, such as "Images (*.png, *.jpg)".
+func (filter Filter) String() string {
+	if len(filter.Extensions) == 0 {
+		return filter.Name
+	}
+	patterns := make([]string, len(filter.Extensions))
+	for i, ext := range filter.Extensions {
+		patterns[i] = "*" + ext
+	}
+	return filter.Name + " (" + strings.Join(patterns, ", ") + ")"
+}
@@ -0,0 +1,33 @@
+package filedialog
+
+import "testing"
+
+func TestFilterMatches(t *testing.T) {
+	images := Filter{Name: "Images", Extensions: []string{".png", ".jpg"}}
+	tt := []struct {
+		filter   Filter
+		name     string
+		expected bool
+	}{
+		{filter: images, name: "a.png", expected: true},
+		{filter: images, name: "a.JPG", expected: true},
+		{filter: images, name: "a.txt", expected: false},
+		{filter: images, name: "png", expected: false},
+		{filter: images, name: "a.png.txt", expected: false},
+		{filter: Filter{Name: "All files"}, name: "anything", expected: true},
+	}
+	for _, tc := range tt {
+		if actual := tc.filter.Matches(tc.name); actual != tc.expected {
+			t.Errorf("%v.Matches(%q) = %v, expected %v", tc.filter, tc.name, actual, tc.expected)
+		}
+	}
+}
+
+func TestFilterString(t *testing.T) {
+	if actual := (Filter{Name: "Images", Extensions: []string{".png", ".jpg"}}).String(); actual != "Images (*.png, *.jpg)" {
+		t.Errorf("unexpected string %q", actual)
+	}
+	if actual := (Filter{Name: "All files"}).String(); actual != "All files" {
+		t.Errorf("unexpected string %q", actual)
+	}
+}
@@ -0,0 +1,5 @@
+// Package filedialog provides a file browser to open and save files, built with imgui alone.
+// It needs no native dialogs of the operating system, and browses any io/fs.FS, such as a
+// directory of the operating system, an embedded file system, or an fstest.MapFS.
+// Paths are slash-separated and relative to the root of the file system, as with io/fs.
+package filedialog
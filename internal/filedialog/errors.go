@@ -0,0 +1,16 @@
+package filedialog
+
+// StringError describes a basic error with static information.
+type StringError string
+
+// Error returns the string itself.
+func (err StringError) Error() string {
+	return string(err)
+}
+
+const (
+	// ErrReadOnly is used in case a folder is created on a file system that does not support it.
+	ErrReadOnly = StringError("file system is read-only")
+	// ErrInvalidName is used in case a file or folder name can not be used.
+	ErrInvalidName = StringError("invalid name")
+)
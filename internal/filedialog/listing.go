@@ -0,0 +1,74 @@
+package filedialog
+
+import (
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+)
+
+// These are the columns of the listing, in the order of the table.
+const (
+	columnName = iota
+	columnSize
+	columnModified
+	columnCount
+)
+
+type entry struct {
+	name     string
+	dir      bool
+	size     int64
+	modified time.Time
+}
+
+// hidden returns true for names that are hidden by convention on Unix.
+func hidden(name string) bool {
+	return strings.HasPrefix(name, ".")
+}
+
+// readDir lists the folders and the files matching the filter of the directory.
+// Entries whose information can not be read, for example broken links, are left out.
+func readDir(fsys fs.FS, dir string, filter Filter, showHidden bool) ([]entry, error) {
+	dirEntries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]entry, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		if !showHidden && hidden(name) {
+			continue
+		}
+		if !dirEntry.IsDir() && !filter.Matches(name) {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue
+		}
+		entries = append(entries, entry{name: name, dir: dirEntry.IsDir(), size: info.Size(), modified: info.ModTime()})
+	}
+	return entries, nil
+}
+
+// sortEntries sorts by given column, with the folders before the files.
+// Ties are sorted by name, so that the order is stable across refreshes.
+func sortEntries(entries []entry, column int, ascending bool) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.dir != b.dir {
+			return a.dir
+		}
+		if !ascending {
+			a, b = b, a
+		}
+		switch {
+		case (column == columnSize) && (a.size != b.size):
+			return a.size < b.size
+		case (column == columnModified) && !a.modified.Equal(b.modified):
+			return a.modified.Before(b.modified)
+		}
+		return strings.ToLower(a.name) < strings.ToLower(b.name)
+	})
+}
@@ -0,0 +1,115 @@
+package filedialog
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func testFS() fstest.MapFS {
+	base := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	return fstest.MapFS{
+		"docs/b.png":      {Data: make([]byte, 30), ModTime: base.Add(1 * time.Hour)},
+		"docs/A.PNG":      {Data: make([]byte, 10), ModTime: base.Add(3 * time.Hour)},
+		"docs/c.txt":      {Data: make([]byte, 20), ModTime: base.Add(2 * time.Hour)},
+		"docs/.hidden":    {Data: make([]byte, 5), ModTime: base},
+		"docs/sub/x.png":  {Data: make([]byte, 1), ModTime: base},
+		"docs/.git/HEAD":  {Data: make([]byte, 1), ModTime: base},
+		"docs/zeta/y.txt": {Data: make([]byte, 1), ModTime: base},
+	}
+}
+
+func names(entries []entry) []string {
+	result := make([]string, len(entries))
+	for i, e := range entries {
+		result[i] = e.name
+	}
+	return result
+}
+
+func assertNames(t *testing.T, entries []entry, expected ...string) {
+	t.Helper()
+	actual := names(entries)
+	if len(actual) != len(expected) {
+		t.Fatalf("got %v, expected %v", actual, expected)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Fatalf("got %v, expected %v", actual, expected)
+		}
+	}
+}
+
+func TestReadDirListsFoldersAndFiles(t *testing.T) {
+	entries, err := readDir(testFS(), "docs", Filter{}, false)
+	if err != nil {
+		t.Fatalf("readDir failed: %v", err)
+	}
+	sortEntries(entries, columnName, true)
+	assertNames(t, entries, "sub", "zeta", "A.PNG", "b.png", "c.txt")
+
+	for _, e := range entries {
+		if (e.name == "b.png") && ((e.size != 30) || e.dir) {
+			t.Errorf("b.png has size %d, dir %v", e.size, e.dir)
+		}
+		if (e.name == "sub") && !e.dir {
+			t.Errorf("sub is not listed as folder")
+		}
+	}
+}
+
+func TestReadDirFiltersFilesByExtensionButKeepsFolders(t *testing.T) {
+	entries, err := readDir(testFS(), "docs", Filter{Name: "Images", Extensions: []string{".png"}}, false)
+	if err != nil {
+		t.Fatalf("readDir failed: %v", err)
+	}
+	sortEntries(entries, columnName, true)
+	assertNames(t, entries, "sub", "zeta", "A.PNG", "b.png")
+}
+
+func TestReadDirShowsHiddenEntriesOnRequest(t *testing.T) {
+	entries, err := readDir(testFS(), "docs", Filter{}, true)
+	if err != nil {
+		t.Fatalf("readDir failed: %v", err)
+	}
+	sortEntries(entries, columnName, true)
+	assertNames(t, entries, ".git", "sub", "zeta", ".hidden", "A.PNG", "b.png", "c.txt")
+}
+
+func TestReadDirFailsForMissingFolder(t *testing.T) {
+	if _, err := readDir(testFS(), "missing", Filter{}, false); err == nil {
+		t.Error("readDir of a missing folder succeeded")
+	}
+}
+
+func TestSortEntries(t *testing.T) {
+	tt := []struct {
+		name      string
+		column    int
+		ascending bool
+		expected  []string
+	}{
+		{name: "name ascending", column: columnName, ascending: true,
+			expected: []string{"sub", "zeta", "A.PNG", "b.png", "c.txt"}},
+		{name: "name descending", column: columnName, ascending: false,
+			expected: []string{"zeta", "sub", "c.txt", "b.png", "A.PNG"}},
+		{name: "size ascending", column: columnSize, ascending: true,
+			expected: []string{"sub", "zeta", "A.PNG", "c.txt", "b.png"}},
+		{name: "size descending", column: columnSize, ascending: false,
+			expected: []string{"zeta", "sub", "b.png", "c.txt", "A.PNG"}},
+		{name: "modified ascending", column: columnModified, ascending: true,
+			expected: []string{"sub", "zeta", "b.png", "c.txt", "A.PNG"}},
+		{name: "modified descending", column: columnModified, ascending: false,
+			expected: []string{"zeta", "sub", "A.PNG", "c.txt", "b.png"}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			entries, err := readDir(testFS(), "docs", Filter{}, false)
+			if err != nil {
+				t.Fatalf("readDir failed: %v", err)
+			}
+			sortEntries(entries, tc.column, tc.ascending)
+			assertNames(t, entries, tc.expected...)
+		})
+	}
+}
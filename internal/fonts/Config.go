@@ -0,0 +1,33 @@
+package fonts
+
+// DefaultSize is the pixel height of the font embedded in imgui.
+const DefaultSize = 13
+
+// Range is an inclusive range of code points.
+type Range struct {
+	First rune
+	Last  rune
+}
+
+// Config describes how a font is rasterized into the atlas.
+type Config struct {
+	// SizePixels is the height of the font in pixels. Zero uses DefaultSize.
+	SizePixels float32
+	// OversampleH and OversampleV control the horizontal and vertical oversampling of glyphs.
+	// Zero values keep the defaults of imgui.
+	OversampleH int
+	OversampleV int
+	// GlyphRanges lists the code points to include.
+	// An empty list includes Basic Latin and Latin-1 Supplement.
+	GlyphRanges []Range
+	// MergeMode merges the glyphs into the previously added font instead of adding a new font.
+	// This is typically used to combine a base font with icons or other scripts.
+	MergeMode bool
+}
+
+func (config Config) sizePixels() float32 {
+	if config.SizePixels <= 0 {
+		return DefaultSize
+	}
+	return config.SizePixels
+}
@@ -0,0 +1,52 @@
+package fonts
+
+import (
+	"github.com/AllenDang/cimgui-go"
+)
+
+// Font is a font registered with a Manager.
+type Font struct {
+	manager *Manager
+	name    string
+	data    []byte
+	config  Config
+
+	handle imgui.Font
+}
+
+// Name returns the name the font was registered with.
+func (font *Font) Name() string {
+	return font.name
+}
+
+// Config returns the current configuration of the font.
+func (font *Font) Config() Config {
+	return font.config
+}
+
+// SetConfig changes the configuration of the font. The change becomes visible
+// with the next atlas rebuild.
+func (font *Font) SetConfig(config Config) {
+	font.config = config
+	font.manager.dirty = true
+}
+
+// SetSize changes the pixel height of the font. The change becomes visible
+// with the next atlas rebuild.
+func (font *Font) SetSize(sizePixels float32) {
+	font.config.SizePixels = sizePixels
+	font.manager.dirty = true
+}
+
+// Handle returns the imgui font, for example to be used with imgui.PushFont().
+// For fonts added in merge mode, this is the font they were merged into.
+// The handle changes with every atlas rebuild, so it should be queried every frame.
+// It is zero until the font has been built.
+func (font *Font) Handle() imgui.Font {
+	return font.handle
+}
+
+// Loaded returns true if the font is part of the current atlas.
+func (font *Font) Loaded() bool {
+	return font.handle != 0
+}
@@ -0,0 +1,58 @@
+package fonts
+
+import (
+	"sort"
+)
+
+// basicRange is always part of a GlyphSet, so that common text and the ellipsis fallback are available.
+var basicRange = Range{First: 0x0020, Last: 0x00FF}
+
+// GlyphSet collects the code points that are actually displayed.
+// Building an atlas only for these keeps it small, even with fonts that cover
+// thousands of glyphs, such as the ones for Chinese, Japanese or Korean.
+type GlyphSet struct {
+	runes map[rune]struct{}
+}
+
+// NewGlyphSet returns a set that contains only the basic Latin range.
+func NewGlyphSet() *GlyphSet {
+	return &GlyphSet{runes: make(map[rune]struct{})}
+}
+
+// AddText adds all code points of the given texts.
+// It returns true if at least one code point was not yet part of the set.
+func (set *GlyphSet) AddText(texts ...string) bool {
+	added := false
+	for _, text := range texts {
+		for _, r := range text {
+			if (r >= basicRange.First) && (r <= basicRange.Last) {
+				continue
+			}
+			if _, known := set.runes[r]; !known {
+				set.runes[r] = struct{}{}
+				added = true
+			}
+		}
+	}
+	return added
+}
+
+// Ranges returns the sorted ranges covering all code points of the set.
+func (set *GlyphSet) Ranges() []Range {
+	runes := make([]rune, 0, len(set.runes))
+	for r := range set.runes {
+		runes = append(runes, r)
+	}
+	sort.Slice(runes, func(a, b int) bool { return runes[a] < runes[b] })
+
+	ranges := []Range{basicRange}
+	for _, r := range runes {
+		last := &ranges[len(ranges)-1]
+		if r == last.Last+1 {
+			last.Last = r
+		} else {
+			ranges = append(ranges, Range{First: r, Last: r})
+		}
+	}
+	return ranges
+}
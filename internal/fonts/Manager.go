@@ -0,0 +1,183 @@
+package fonts
+
+import (
+	"fmt"
+	"os"
+	"unsafe"
+
+	"github.com/AllenDang/cimgui-go"
+)
+
+// Manager keeps the list of fonts and rebuilds the font atlas when they change.
+// A Manager must only be used from the thread that runs the frame loop.
+type Manager struct {
+	atlas imgui.FontAtlas
+	fonts []*Font
+	dirty bool
+
+	// glyphRanges holds the range arrays that are referenced by the atlas until its next rebuild.
+	glyphRanges []unsafe.Pointer
+}
+
+// NewManager returns a manager for given atlas, typically imgui.CurrentIO().Fonts().
+func NewManager(atlas imgui.FontAtlas) *Manager {
+	return &Manager{
+		atlas: atlas,
+		dirty: true,
+	}
+}
+
+// Dispose releases the memory held for the atlas.
+func (manager *Manager) Dispose() {
+	manager.atlas.Clear()
+	manager.freeGlyphRanges()
+	for _, font := range manager.fonts {
+		font.handle = 0
+	}
+}
+
+// Fonts returns the registered fonts, in the order they are added to the atlas.
+func (manager *Manager) Fonts() []*Font {
+	return append([]*Font(nil), manager.fonts...)
+}
+
+// AddDefault registers the font that is embedded in imgui.
+func (manager *Manager) AddDefault(config Config) *Font {
+	return manager.add("default", nil, config)
+}
+
+// AddFromBytes registers a TrueType or OpenType font from memory.
+// The data is copied when the atlas is built, the slice must not be modified afterwards.
+func (manager *Manager) AddFromBytes(name string, data []byte, config Config) (*Font, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("font %s has no data", name)
+	}
+	return manager.add(name, data, config), nil
+}
+
+// AddFromFile registers a TrueType or OpenType font file.
+func (manager *Manager) AddFromFile(path string, config Config) (*Font, error) {
+	return manager.addFace(Face{Path: path}, config)
+}
+
+func (manager *Manager) addFace(face Face, config Config) (*Font, error) {
+	name := face.Name
+	if name == "" {
+		name = face.Path
+	}
+	switch {
+	case len(face.Data) > 0:
+		return manager.AddFromBytes(name, face.Data, config)
+	case face.Path != "":
+		data, err := os.ReadFile(face.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read font: %w", err)
+		}
+		return manager.AddFromBytes(name, data, config)
+	default:
+		return manager.AddDefault(config), nil
+	}
+}
+
+// Remove unregisters a font. The change becomes visible with the next atlas rebuild.
+func (manager *Manager) Remove(font *Font) {
+	for i, registered := range manager.fonts {
+		if registered == font {
+			manager.fonts = append(manager.fonts[:i], manager.fonts[i+1:]...)
+			font.handle = 0
+			manager.dirty = true
+			return
+		}
+	}
+}
+
+// Invalidate causes the atlas to be rebuilt with the next call to Apply.
+func (manager *Manager) Invalidate() {
+	manager.dirty = true
+}
+
+// Apply rebuilds the font atlas if any font was changed since the last call.
+// It must be called between frames, before the renderer prepares the next frame,
+// as the atlas is locked between imgui.NewFrame() and imgui.Render().
+// It returns true if the atlas was rebuilt. The atlas is built right away, so the renderer must
+// then upload the font texture again; it does not notice the change on its own.
+func (manager *Manager) Apply() bool {
+	if !manager.dirty {
+		return false
+	}
+	manager.dirty = false
+
+	manager.atlas.Clear()
+	manager.freeGlyphRanges()
+	if len(manager.fonts) == 0 {
+		manager.atlas.AddFontDefault()
+	}
+	for index, font := range manager.fonts {
+		font.handle = manager.build(font, index == 0)
+	}
+	manager.atlas.Build()
+	return true
+}
+
+func (manager *Manager) add(name string, data []byte, config Config) *Font {
+	font := &Font{
+		manager: manager,
+		name:    name,
+		data:    data,
+		config:  config,
+	}
+	manager.fonts = append(manager.fonts, font)
+	manager.dirty = true
+	return font
+}
+
+func (manager *Manager) build(font *Font, first bool) imgui.Font {
+	fontConfig := imgui.NewFontConfig()
+	defer fontConfig.Destroy()
+
+	fontConfig.SetSizePixels(font.config.sizePixels())
+	if font.config.OversampleH > 0 {
+		fontConfig.SetOversampleH(int32(font.config.OversampleH))
+	}
+	if font.config.OversampleV > 0 {
+		fontConfig.SetOversampleV(int32(font.config.OversampleV))
+	}
+	// imgui can not merge into a font that does not exist.
+	fontConfig.SetMergeMode(font.config.MergeMode && !first)
+	glyphRanges := manager.allocGlyphRanges(font.config.GlyphRanges)
+	fontConfig.SetGlyphRanges(glyphRanges)
+
+	if font.data == nil {
+		return manager.atlas.AddFontDefaultV(fontConfig)
+	}
+	// The atlas takes ownership of the copy and releases it with the next clear.
+	data := imgui.MemAlloc(uint64(len(font.data)))
+	copy(unsafe.Slice((*byte)(data), len(font.data)), font.data)
+	return manager.atlas.AddFontFromMemoryTTFV(data, int32(len(font.data)), font.config.sizePixels(), fontConfig, glyphRanges)
+}
+
+// allocGlyphRanges copies the ranges into a zero-terminated array in imgui memory.
+// It returns nil for an empty list, which selects the default ranges.
+func (manager *Manager) allocGlyphRanges(ranges []Range) *imgui.Wchar {
+	if len(ranges) == 0 {
+		return nil
+	}
+	count := len(ranges)*2 + 1
+	var entry imgui.Wchar
+	memory := imgui.MemAlloc(uint64(count) * uint64(unsafe.Sizeof(entry)))
+	entries := unsafe.Slice((*imgui.Wchar)(memory), count)
+	for i, r := range ranges {
+		entries[i*2] = imgui.Wchar(r.First)
+		entries[i*2+1] = imgui.Wchar(r.Last)
+	}
+	entries[count-1] = 0
+	manager.glyphRanges = append(manager.glyphRanges, memory)
+	return &entries[0]
+}
+
+func (manager *Manager) freeGlyphRanges() {
+	for _, memory := range manager.glyphRanges {
+		imgui.MemFree(memory)
+	}
+	manager.glyphRanges = nil
+}
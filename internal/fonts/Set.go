@@ -0,0 +1,82 @@
+package fonts
+
+import (
+	"github.com/AllenDang/cimgui-go"
+)
+
+// Face identifies the data of a font, either in memory or as a file.
+// The zero value identifies the font embedded in imgui.
+type Face struct {
+	// Name is used to identify the face in messages. It defaults to the path.
+	Name string
+	// Data contains the TrueType or OpenType font. If empty, the font is read from Path.
+	Data []byte
+	// Path is the file to read the font from.
+	Path string
+}
+
+// Set combines a base font with fallback fonts into one imgui font.
+// Glyphs are taken from the first font of the set that provides them.
+// The atlas only contains the glyphs of the texts that were announced to the set.
+type Set struct {
+	fonts  []*Font
+	glyphs *GlyphSet
+}
+
+// NewSet registers the base font and the fallback fonts with the manager, merged into one font of given size.
+func NewSet(manager *Manager, sizePixels float32, base Face, fallbacks ...Face) (*Set, error) {
+	set := &Set{glyphs: NewGlyphSet()}
+	for index, face := range append([]Face{base}, fallbacks...) {
+		config := Config{
+			SizePixels:  sizePixels,
+			GlyphRanges: set.glyphs.Ranges(),
+			MergeMode:   index > 0,
+		}
+		font, err := manager.addFace(face, config)
+		if err != nil {
+			for _, added := range set.fonts {
+				manager.Remove(added)
+			}
+			return nil, err
+		}
+		set.fonts = append(set.fonts, font)
+	}
+	return set, nil
+}
+
+// Handle returns the imgui font of the set. See Font.Handle().
+func (set *Set) Handle() imgui.Font {
+	return set.fonts[0].Handle()
+}
+
+// SetSize changes the pixel height of all fonts of the set.
+func (set *Set) SetSize(sizePixels float32) {
+	for _, font := range set.fonts {
+		font.SetSize(sizePixels)
+	}
+}
+
+// AddText announces texts that will be displayed with this set.
+// This is typically done ahead of time for all static texts of an application.
+// New glyphs become available with the next atlas rebuild.
+func (set *Set) AddText(texts ...string) {
+	if !set.glyphs.AddText(texts...) {
+		return
+	}
+	ranges := set.glyphs.Ranges()
+	for _, font := range set.fonts {
+		config := font.Config()
+		config.GlyphRanges = ranges
+		font.SetConfig(config)
+	}
+}
+
+// Use announces the text and returns it unchanged, for inline use such as
+//
+//	imgui.Text(set.Use(label))
+//
+// Text that contains new glyphs is displayed properly from the next frame on.
+func (set *Set) Use(text string) string {
+	set.AddText(text)
+	return text
+}
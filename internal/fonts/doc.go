@@ -0,0 +1,7 @@
+// Package fonts manages the fonts of the imgui font atlas.
+// Fonts are registered from memory or from files, and the atlas is rebuilt
+// between frames whenever the set of fonts or their configuration changes.
+// Manager.Apply builds the atlas right away, so the renderers do not notice the change on their own.
+// It reports the rebuild instead, upon which the caller has the renderer upload the font texture again,
+// as example.Run does with RebuildFonts.
+package fonts
@@ -0,0 +1,158 @@
+package fonts
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// FallbackFamilies lists font files of common operating systems and distributions that cover
+// Chinese and Japanese, Korean, and Thai; one list per script, each in order of preference.
+// Only the first installed file of each list is needed, as the others cover the same glyphs.
+var FallbackFamilies = [][]string{
+	{"NotoSansCJK-Regular.ttc", "NotoSansCJKsc-Regular.otf", "wqy-microhei.ttc", "wqy-zenhei.ttc",
+		"DroidSansFallbackFull.ttf", "msyh.ttc", "PingFang.ttc"},
+	{"NotoSansCJK-Regular.ttc", "NanumGothic.ttf", "malgun.ttf", "AppleSDGothicNeo.ttc"},
+	{"NotoSansThai-Regular.ttf", "Loma.ttf", "Garuda.ttf", "tahoma.ttf", "Thonburi.ttc"},
+}
+
+var fontconfigDirPattern = regexp.MustCompile(`<dir(?:\s+prefix="([^"]*)")?[^>]*>\s*([^<]+?)\s*</dir>`)
+
+// SystemFontDirs returns the directories that typically contain installed fonts.
+// On Linux and BSD systems, the directories configured for fontconfig are included.
+func SystemFontDirs() []string {
+	home, _ := os.UserHomeDir()
+	var dirs []string
+	switch runtime.GOOS {
+	case "windows":
+		dirs = append(dirs, filepath.Join(os.Getenv("WINDIR"), "Fonts"),
+			filepath.Join(os.Getenv("LOCALAPPDATA"), "Microsoft", "Windows", "Fonts"))
+	case "darwin":
+		dirs = append(dirs, "/System/Library/Fonts", "/Library/Fonts", filepath.Join(home, "Library", "Fonts"))
+	default:
+		dirs = append(dirs, fontconfigDirs(home)...)
+		dirs = append(dirs, "/usr/share/fonts", "/usr/local/share/fonts",
+			filepath.Join(xdgDataHome(home), "fonts"), filepath.Join(home, ".fonts"))
+	}
+	return unique(dirs)
+}
+
+// FindSystemFonts returns the faces of all files with given names in the system font directories.
+// Names are compared case-insensitively, the result keeps the order of the names.
+func FindSystemFonts(fileNames ...string) []Face {
+	found := systemFontFiles()
+	var faces []Face
+	for _, fileName := range fileNames {
+		if path, exists := found[strings.ToLower(fileName)]; exists {
+			faces = append(faces, Face{Path: path})
+		}
+	}
+	return faces
+}
+
+// FindFallbackFonts returns the face of the first installed file of each family, such as FallbackFamilies.
+// Files that are the choice of more than one family are returned once.
+func FindFallbackFonts(families [][]string) []Face {
+	found := systemFontFiles()
+	var faces []Face
+	chosen := make(map[string]bool)
+	for _, family := range families {
+		for _, fileName := range family {
+			path, exists := found[strings.ToLower(fileName)]
+			if !exists {
+				continue
+			}
+			if !chosen[path] {
+				chosen[path] = true
+				faces = append(faces, Face{Path: path})
+			}
+			break
+		}
+	}
+	return faces
+}
+
+var (
+	systemFontsOnce sync.Once
+	systemFonts     map[string]string
+)
+
+// systemFontFiles returns the paths of the files in the system font directories by their lower-case name.
+// The directories are only walked once, directories that are nested in others or linked twice are skipped.
+func systemFontFiles() map[string]string {
+	systemFontsOnce.Do(func() {
+		systemFonts = make(map[string]string)
+		walked := make(map[string]bool)
+		for _, dir := range SystemFontDirs() {
+			_ = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+				if err != nil {
+					return nil
+				}
+				if entry.IsDir() {
+					resolved, err := filepath.EvalSymlinks(path)
+					if (err != nil) || walked[resolved] {
+						return fs.SkipDir
+					}
+					walked[resolved] = true
+					return nil
+				}
+				name := strings.ToLower(entry.Name())
+				if _, known := systemFonts[name]; !known {
+					systemFonts[name] = path
+				}
+				return nil
+			})
+		}
+	})
+	return systemFonts
+}
+
+// fontconfigDirs extracts the font directories from the fontconfig configuration files.
+func fontconfigDirs(home string) []string {
+	files := []string{"/etc/fonts/fonts.conf"}
+	confD, _ := filepath.Glob("/etc/fonts/conf.d/*.conf")
+	files = append(files, confD...)
+
+	var dirs []string
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		for _, match := range fontconfigDirPattern.FindAllStringSubmatch(string(data), -1) {
+			prefix, dir := match[1], match[2]
+			switch {
+			case prefix == "xdg":
+				dir = filepath.Join(xdgDataHome(home), dir)
+			case strings.HasPrefix(dir, "~"):
+				dir = filepath.Join(home, dir[1:])
+			}
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+func xdgDataHome(home string) string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return dir
+	}
+	return filepath.Join(home, ".local", "share")
+}
+
+func unique(dirs []string) []string {
+	seen := make(map[string]bool)
+	result := dirs[:0]
+	for _, dir := range dirs {
+		dir = filepath.Clean(dir)
+		if !seen[dir] {
+			seen[dir] = true
+			result = append(result, dir)
+		}
+	}
+	return result
+}
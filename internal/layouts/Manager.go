@@ -0,0 +1,281 @@
+package layouts
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/AllenDang/cimgui-go"
+)
+
+const (
+	// fileExtension is the extension of the files that hold the layouts.
+	fileExtension = ".ini"
+	saveAsPopupID = "Save layout"
+)
+
+// Builder creates the default layout by splitting the given dock space node
+// and docking windows into the resulting nodes with the DockBuilder API.
+type Builder func(dockspace imgui.ID)
+
+// Manager hosts the dock space over the main viewport and keeps the named layouts.
+// A Manager must only be used from the thread that runs the frame loop.
+type Manager struct {
+	dir     string
+	builder Builder
+
+	names   []string
+	current string
+	err     error
+
+	// pending holds ini data that is loaded before the next frame.
+	pending    string
+	hasPending bool
+	// rebuild requests the default layout for the next frame.
+	rebuild bool
+	checked bool
+
+	newName    string
+	openSaveAs bool
+}
+
+// NewManager returns a manager that stores layouts in given directory.
+// The builder creates the default layout; it may be nil if there is none.
+func NewManager(dir string, builder Builder) *Manager {
+	manager := &Manager{
+		dir:     dir,
+		builder: builder,
+	}
+	manager.Refresh()
+	return manager
+}
+
+// Names returns the names of the saved layouts, in alphabetical order.
+func (manager *Manager) Names() []string {
+	return append([]string(nil), manager.names...)
+}
+
+// Current returns the name of the layout that was last saved or loaded.
+// It is empty if the default layout, or the one of the last session, is shown.
+func (manager *Manager) Current() string {
+	return manager.current
+}
+
+// Err returns the error of the last operation that was started from the menu, or nil.
+func (manager *Manager) Err() error {
+	return manager.err
+}
+
+// Refresh reads the names of the saved layouts from the directory.
+func (manager *Manager) Refresh() {
+	manager.names = nil
+	if manager.dir == "" {
+		return
+	}
+	entries, err := os.ReadDir(manager.dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.Type().IsRegular() && strings.HasSuffix(name, fileExtension) {
+			manager.names = append(manager.names, strings.TrimSuffix(name, fileExtension))
+		}
+	}
+	sort.Strings(manager.names)
+}
+
+// Save stores the current layout under given name, replacing a previous layout of the same name.
+func (manager *Manager) Save(name string) error {
+	path, err := manager.path(name)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(manager.dir, 0o755)
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(path, []byte(imgui.SaveIniSettingsToMemory()), 0o644)
+	if err != nil {
+		return err
+	}
+	manager.current = name
+	manager.Refresh()
+	return nil
+}
+
+// Load reads the layout of given name. It is applied with the next call to Apply.
+func (manager *Manager) Load(name string) error {
+	path, err := manager.path(name)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	manager.pending = string(data)
+	manager.hasPending = true
+	manager.rebuild = false
+	manager.current = name
+	return nil
+}
+
+// Delete removes the saved layout of given name.
+func (manager *Manager) Delete(name string) error {
+	path, err := manager.path(name)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if err != nil {
+		return err
+	}
+	if manager.current == name {
+		manager.current = ""
+	}
+	manager.Refresh()
+	return nil
+}
+
+// Reset requests the default layout to be built in the next frame.
+func (manager *Manager) Reset() {
+	manager.hasPending = false
+	manager.rebuild = true
+	manager.current = ""
+}
+
+// Apply loads a pending layout. It must be called before imgui.NewFrame().
+func (manager *Manager) Apply() {
+	if !manager.hasPending {
+		return
+	}
+	imgui.LoadIniSettingsFromMemory(manager.pending)
+	manager.pending = ""
+	manager.hasPending = false
+}
+
+// DockSpace places the dock space over the main viewport and returns its ID.
+// The central node lets the main viewport shine through. The default layout is built
+// if requested with Reset, or if no layout exists from a previous session.
+// DockSpace must be called after the main menu bar, and before any windows are begun.
+func (manager *Manager) DockSpace() imgui.ID {
+	if !manager.checked {
+		// The settings of the previous session are loaded in the first imgui.NewFrame().
+		// The dock space node itself is only created by the following call, so it must be tested before.
+		manager.checked = true
+		manager.rebuild = manager.rebuild || !hasDockSpaceSettings(imgui.SaveIniSettingsToMemory())
+	}
+
+	viewport := imgui.MainViewport()
+	id := imgui.DockSpaceOverViewportV(viewport, imgui.DockNodeFlagsPassthruCentralNode, 0)
+	if manager.rebuild && (manager.builder != nil) {
+		imgui.InternalDockBuilderRemoveNode(id)
+		imgui.InternalDockBuilderAddNodeV(id, imgui.DockNodeFlagsDockSpace|imgui.DockNodeFlagsPassthruCentralNode)
+		imgui.InternalDockBuilderSetNodeSize(id, viewport.WorkSize())
+		manager.builder(id)
+		imgui.InternalDockBuilderFinish(id)
+	}
+	manager.rebuild = false
+	return id
+}
+
+// Menu adds the "Layout" menu with the saved layouts. It must be called within a menu bar.
+func (manager *Manager) Menu() {
+	if !imgui.BeginMenu("Layout") {
+		return
+	}
+	if imgui.MenuItemBoolV("Reset to default", "", false, manager.builder != nil) {
+		manager.Reset()
+	}
+	imgui.Separator()
+	if len(manager.names) == 0 {
+		imgui.TextDisabled("No saved layouts")
+	}
+	for _, name := range manager.names {
+		if imgui.MenuItemBoolV(name, "", name == manager.current, true) {
+			manager.err = manager.Load(name)
+		}
+	}
+	imgui.Separator()
+	if imgui.MenuItemBoolV("Save", "", false, manager.current != "") {
+		manager.err = manager.Save(manager.current)
+	}
+	if imgui.MenuItemBool("Save as...") {
+		manager.newName = manager.current
+		manager.openSaveAs = true
+	}
+	if imgui.BeginMenuV("Delete", len(manager.names) > 0) {
+		for _, name := range manager.names {
+			if imgui.MenuItemBool(name) {
+				manager.err = manager.Delete(name)
+			}
+		}
+		imgui.EndMenu()
+	}
+	if manager.err != nil {
+		imgui.Separator()
+		imgui.TextDisabled(manager.err.Error())
+	}
+	imgui.EndMenu()
+}
+
+// Dialogs shows the popups that are opened from the menu.
+// It must be called outside of any menu, for example right after the main menu bar.
+func (manager *Manager) Dialogs() {
+	if manager.openSaveAs {
+		manager.openSaveAs = false
+		imgui.OpenPopupStr(saveAsPopupID)
+	}
+	if !imgui.BeginPopupModalV(saveAsPopupID, nil, imgui.WindowFlagsAlwaysAutoResize) {
+		return
+	}
+	if imgui.IsWindowAppearing() {
+		imgui.SetKeyboardFocusHere()
+	}
+	confirmed := imgui.InputTextWithHint("##name", "Name", &manager.newName, imgui.InputTextFlagsEnterReturnsTrue, nil)
+	_, nameErr := manager.path(manager.newName)
+	imgui.BeginDisabledV(nameErr != nil)
+	confirmed = imgui.Button("Save") || confirmed
+	imgui.EndDisabled()
+	imgui.SameLine()
+	if imgui.Button("Cancel") {
+		imgui.CloseCurrentPopup()
+	}
+	if confirmed && (nameErr == nil) {
+		manager.err = manager.Save(manager.newName)
+		imgui.CloseCurrentPopup()
+	}
+	imgui.EndPopup()
+}
+
+func (manager *Manager) path(name string) (string, error) {
+	if manager.dir == "" {
+		return "", ErrNoDirectory
+	}
+	if !validName(name) {
+		return "", ErrInvalidName
+	}
+	return filepath.Join(manager.dir, name+fileExtension), nil
+}
+
+// validName returns true if the name can be used as a file name on all platforms.
+func validName(name string) bool {
+	if (strings.TrimSpace(name) != name) || (name == "") || strings.HasPrefix(name, ".") {
+		return false
+	}
+	return !strings.ContainsAny(name, `/\:*?"<>|`)
+}
+
+// hasDockSpaceSettings returns true if the ini data contains the node of a dock space.
+func hasDockSpaceSettings(ini string) bool {
+	scanner := bufio.NewScanner(strings.NewReader(ini))
+	for scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), "DockSpace ") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,5 @@
+// Package layouts manages the docking layout of an application.
+// It hosts a dock space over the main viewport, builds a default layout with
+// the DockBuilder API, and saves and restores named layouts as imgui ini data
+// in a directory, typically below the user configuration directory.
+package layouts
@@ -0,0 +1,16 @@
+package layouts
+
+// StringError describes a basic error with static information.
+type StringError string
+
+// Error returns the string itself.
+func (err StringError) Error() string {
+	return string(err)
+}
+
+const (
+	// ErrInvalidName is returned for layout names that can not be used as file names.
+	ErrInvalidName = StringError("invalid layout name")
+	// ErrNoDirectory is returned if layouts are to be stored without a directory.
+	ErrNoDirectory = StringError("no layout directory")
+)
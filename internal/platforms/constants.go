@@ -7,5 +7,4 @@ const (
 	mouseButtonPrimary   = 0
 	mouseButtonSecondary = 1
 	mouseButtonTertiary  = 2
-	mouseButtonCount     = 3
 )
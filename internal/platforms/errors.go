@@ -11,4 +11,8 @@ func (err StringError) Error() string {
 const (
 	// ErrUnsupportedClientAPI is used in case the API is not available by the platform.
 	ErrUnsupportedClientAPI = StringError("unsupported ClientAPI")
+	// ErrUnknownMonitor is used in case a monitor is not connected.
+	ErrUnknownMonitor = StringError("unknown monitor")
+	// ErrUnsupportedDisplayMode is used in case the display mode is not available by the platform.
+	ErrUnsupportedDisplayMode = StringError("unsupported display mode")
 )
@@ -7,6 +7,9 @@ import (
 
 	"github.com/AllenDang/cimgui-go"
 	"github.com/go-gl/glfw/v3.3/glfw"
+
+	"github.com/ptxmac/cimgui-go-examples/internal/display"
+	"github.com/ptxmac/cimgui-go-examples/internal/events"
 )
 
 // GLFWClientAPI identifies the render system that shall be initialized.
@@ -23,11 +26,26 @@ type GLFW struct {
 	imguiIO imgui.IO
 
 	window *glfw.Window
+	title  string
 
 	keyMap map[glfw.Key]imgui.Key
 
-	time             float64
-	mouseJustPressed [3]bool
+	clientAPI GLFWClientAPI
+
+	time         float64
+	contentScale float32
+	windowed     WindowState
+
+	displayMode       display.Mode
+	windowedMaximized bool
+
+	events []events.Event
+
+	windowRenderer  WindowRenderer
+	viewports       map[imgui.ID]*viewportWindow
+	monitorsChanged bool
+
+	err error
 }
 
 // NewGLFW attempts to initialize a GLFW context.
@@ -39,21 +57,16 @@ func NewGLFW(io imgui.IO, clientAPI GLFWClientAPI) (*GLFW, error) {
 		return nil, fmt.Errorf("failed to initialize glfw: %w", err)
 	}
 
-	switch clientAPI {
-	case GLFWClientAPIOpenGL2:
-		glfw.WindowHint(glfw.ContextVersionMajor, 2)
-		glfw.WindowHint(glfw.ContextVersionMinor, 1)
-	case GLFWClientAPIOpenGL3:
-		glfw.WindowHint(glfw.ContextVersionMajor, 3)
-		glfw.WindowHint(glfw.ContextVersionMinor, 2)
-		glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
-		glfw.WindowHint(glfw.OpenGLForwardCompatible, 1)
-	default:
+	if !setClientAPIHints(clientAPI) {
 		glfw.Terminate()
 		return nil, ErrUnsupportedClientAPI
 	}
+	// Let the window size follow the content scale of the monitor on platforms where
+	// window coordinates are pixels. On macOS, the framebuffer is scaled instead.
+	glfw.WindowHint(glfw.ScaleToMonitor, glfw.True)
 
-	window, err := glfw.CreateWindow(windowWidth, windowHeight, "CImGui-Go GLFW+"+string(clientAPI)+" example", nil, nil)
+	title := "CImGui-Go GLFW+" + string(clientAPI) + " example"
+	window, err := glfw.CreateWindow(windowWidth, windowHeight, title, nil, nil)
 	if err != nil {
 		glfw.Terminate()
 		return nil, fmt.Errorf("failed to create window: %w", err)
@@ -61,32 +74,68 @@ func NewGLFW(io imgui.IO, clientAPI GLFWClientAPI) (*GLFW, error) {
 	window.MakeContextCurrent()
 	glfw.SwapInterval(1)
 
+	contentScale, _ := window.GetContentScale()
 	platform := &GLFW{
-		imguiIO: io,
-		window:  window,
+		imguiIO:      io,
+		window:       window,
+		title:        title,
+		clientAPI:    clientAPI,
+		contentScale: contentScale,
 	}
+	platform.trackWindowedGeometry()
 	platform.setKeyMapping()
 	platform.installCallbacks()
+	platform.installViewportCallbacks()
 
 	return platform, nil
 }
 
 // Dispose cleans up the resources.
 func (platform *GLFW) Dispose() {
+	platform.removeViewportCallbacks()
 	platform.window.Destroy()
 	glfw.Terminate()
 }
 
 // ShouldStop returns true if the window is to be closed.
+// Requests of the user to close the window do not stop the platform by themselves, they are
+// reported as events.Close instead. The application decides whether to call Stop.
 func (platform *GLFW) ShouldStop() bool {
 	return platform.window.ShouldClose()
 }
 
+// Stop lets ShouldStop return true.
+func (platform *GLFW) Stop() {
+	platform.window.SetShouldClose(true)
+}
+
+// Err returns a failure of the windowing system that prevents further frames, or nil.
+func (platform *GLFW) Err() error {
+	return platform.err
+}
+
 // ProcessEvents handles all pending window events.
 func (platform *GLFW) ProcessEvents() {
+	defer platform.recoverFailure()
 	glfw.PollEvents()
 }
 
+// recoverFailure keeps the error of a GLFW function as the failure of the platform.
+// GLFW panics if a function fails; other panics are passed on.
+func (platform *GLFW) recoverFailure() {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+	err, isGLFWError := recovered.(*glfw.Error)
+	if !isGLFWError {
+		panic(recovered)
+	}
+	if platform.err == nil {
+		platform.err = err
+	}
+}
+
 // DisplaySize returns the dimension of the display.
 func (platform *GLFW) DisplaySize() [2]float32 {
 	w, h := platform.window.GetSize()
@@ -99,11 +148,62 @@ func (platform *GLFW) FramebufferSize() [2]float32 {
 	return [2]float32{float32(w), float32(h)}
 }
 
+// ContentScale returns the ratio between the current DPI and the default DPI of the platform.
+// It is updated when the window moves to a monitor with a different scale.
+func (platform *GLFW) ContentScale() float32 {
+	return platform.contentScale
+}
+
+// MonitorDPI returns the physical resolution of the monitor that contains the center of the window.
+// It returns zero if the monitor does not report its physical size.
+func (platform *GLFW) MonitorDPI() float32 {
+	monitor := platform.currentMonitor()
+	if monitor == nil {
+		return 0
+	}
+	widthMM, _ := monitor.GetPhysicalSize()
+	mode := monitor.GetVideoMode()
+	if (widthMM <= 0) || (mode == nil) {
+		return 0
+	}
+	const millimetersPerInch = 25.4
+	return float32(mode.Width) / (float32(widthMM) / millimetersPerInch)
+}
+
+// currentMonitor returns the monitor that contains the center of the window, or the primary monitor.
+func (platform *GLFW) currentMonitor() *glfw.Monitor {
+	if monitor := platform.window.GetMonitor(); monitor != nil {
+		return monitor
+	}
+	x, y := platform.window.GetPos()
+	width, height := platform.window.GetSize()
+	centerX, centerY := x+width/2, y+height/2
+	for _, monitor := range glfw.GetMonitors() {
+		monitorX, monitorY := monitor.GetPos()
+		mode := monitor.GetVideoMode()
+		if mode == nil {
+			continue
+		}
+		if (centerX >= monitorX) && (centerX < monitorX+mode.Width) && (centerY >= monitorY) && (centerY < monitorY+mode.Height) {
+			return monitor
+		}
+	}
+	return glfw.GetPrimaryMonitor()
+}
+
 // NewFrame marks the begin of a render pass. It forwards all current state to imgui IO.
 func (platform *GLFW) NewFrame() {
+	defer platform.recoverFailure()
 	// Setup display size (every frame to accommodate for window resizing)
 	displaySize := platform.DisplaySize()
 	platform.imguiIO.SetDisplaySize(imgui.Vec2{X: displaySize[0], Y: displaySize[1]})
+	framebufferSize := platform.FramebufferSize()
+	if (displaySize[0] > 0) && (displaySize[1] > 0) {
+		platform.imguiIO.SetDisplayFramebufferScale(imgui.Vec2{
+			X: framebufferSize[0] / displaySize[0],
+			Y: framebufferSize[1] / displaySize[1],
+		})
+	}
 
 	// Setup time step
 	currentTime := glfw.GetTime()
@@ -112,67 +212,120 @@ func (platform *GLFW) NewFrame() {
 	}
 	platform.time = currentTime
 
-	// Setup inputs
-	if platform.window.GetAttrib(glfw.Focused) != 0 {
-		x, y := platform.window.GetCursorPos()
-		platform.imguiIO.SetMousePos(imgui.Vec2{X: float32(x), Y: float32(y)})
-	} else {
-		platform.imguiIO.SetMousePos(imgui.Vec2{X: -math.MaxFloat32, Y: -math.MaxFloat32})
-	}
+	platform.updateMonitors()
 
-	for i := 0; i < len(platform.mouseJustPressed); i++ {
-		down := platform.mouseJustPressed[i] || (platform.window.GetMouseButton(glfwButtonIDByIndex[i]) == glfw.Press)
-		platform.imguiIO.SetMouseButtonDown(i, down)
-		platform.mouseJustPressed[i] = false
-	}
+	// Inputs are not forwarded here; they are emitted as events, see Events().
 }
 
 // PostRender performs a buffer swap.
+// Secondary viewports may have changed the current context, so the one of the main window is restored first.
 func (platform *GLFW) PostRender() {
+	defer platform.recoverFailure()
+	platform.window.MakeContextCurrent()
 	platform.window.SwapBuffers()
 }
 
 func (platform *GLFW) setKeyMapping() {
-	// Keyboard mapping. ImGui will use those indices to peek into the io.KeysDown[] array.
-
+	// Keyboard mapping. Keys are identified by their position on a US keyboard, as imgui does.
 	platform.keyMap = map[glfw.Key]imgui.Key{
-		glfw.KeyTab:       imgui.KeyTab,
-		glfw.KeyLeft:      imgui.KeyLeftArrow,
-		glfw.KeyRight:     imgui.KeyRightArrow,
-		glfw.KeyUp:        imgui.KeyUpArrow,
-		glfw.KeyDown:      imgui.KeyDownArrow,
-		glfw.KeyPageUp:    imgui.KeyPageUp,
-		glfw.KeyPageDown:  imgui.KeyPageDown,
-		glfw.KeyHome:      imgui.KeyHome,
-		glfw.KeyEnd:       imgui.KeyEnd,
-		glfw.KeyInsert:    imgui.KeyInsert,
-		glfw.KeyDelete:    imgui.KeyDelete,
-		glfw.KeyBackspace: imgui.KeyBackspace,
-		glfw.KeySpace:     imgui.KeySpace,
-		glfw.KeyEnter:     imgui.KeyEnter,
-		glfw.KeyEscape:    imgui.KeyEscape,
-		glfw.KeyA:         imgui.KeyA,
-		glfw.KeyC:         imgui.KeyC,
-		glfw.KeyV:         imgui.KeyV,
-		glfw.KeyX:         imgui.KeyX,
-		glfw.KeyY:         imgui.KeyY,
-		glfw.KeyZ:         imgui.KeyZ,
-
-		glfw.KeyLeftControl:  imgui.ModCtrl,
-		glfw.KeyRightControl: imgui.ModCtrl,
-		glfw.KeyLeftAlt:      imgui.ModAlt,
-		glfw.KeyRightAlt:     imgui.ModAlt,
-		glfw.KeyLeftSuper:    imgui.ModSuper,
-		glfw.KeyRightSuper:   imgui.ModSuper,
+		glfw.KeyTab:          imgui.KeyTab,
+		glfw.KeyLeft:         imgui.KeyLeftArrow,
+		glfw.KeyRight:        imgui.KeyRightArrow,
+		glfw.KeyUp:           imgui.KeyUpArrow,
+		glfw.KeyDown:         imgui.KeyDownArrow,
+		glfw.KeyPageUp:       imgui.KeyPageUp,
+		glfw.KeyPageDown:     imgui.KeyPageDown,
+		glfw.KeyHome:         imgui.KeyHome,
+		glfw.KeyEnd:          imgui.KeyEnd,
+		glfw.KeyInsert:       imgui.KeyInsert,
+		glfw.KeyDelete:       imgui.KeyDelete,
+		glfw.KeyBackspace:    imgui.KeyBackspace,
+		glfw.KeySpace:        imgui.KeySpace,
+		glfw.KeyEnter:        imgui.KeyEnter,
+		glfw.KeyEscape:       imgui.KeyEscape,
+		glfw.KeyApostrophe:   imgui.KeyApostrophe,
+		glfw.KeyComma:        imgui.KeyComma,
+		glfw.KeyMinus:        imgui.KeyMinus,
+		glfw.KeyPeriod:       imgui.KeyPeriod,
+		glfw.KeySlash:        imgui.KeySlash,
+		glfw.KeySemicolon:    imgui.KeySemicolon,
+		glfw.KeyEqual:        imgui.KeyEqual,
+		glfw.KeyLeftBracket:  imgui.KeyLeftBracket,
+		glfw.KeyBackslash:    imgui.KeyBackslash,
+		glfw.KeyRightBracket: imgui.KeyRightBracket,
+		glfw.KeyGraveAccent:  imgui.KeyGraveAccent,
+		glfw.KeyCapsLock:     imgui.KeyCapsLock,
+		glfw.KeyScrollLock:   imgui.KeyScrollLock,
+		glfw.KeyNumLock:      imgui.KeyNumLock,
+		glfw.KeyPrintScreen:  imgui.KeyPrintScreen,
+		glfw.KeyPause:        imgui.KeyPause,
+		glfw.KeyKPDecimal:    imgui.KeyKeypadDecimal,
+		glfw.KeyKPDivide:     imgui.KeyKeypadDivide,
+		glfw.KeyKPMultiply:   imgui.KeyKeypadMultiply,
+		glfw.KeyKPSubtract:   imgui.KeyKeypadSubtract,
+		glfw.KeyKPAdd:        imgui.KeyKeypadAdd,
+		glfw.KeyKPEnter:      imgui.KeyKeypadEnter,
+		glfw.KeyKPEqual:      imgui.KeyKeypadEqual,
+		glfw.KeyLeftShift:    imgui.KeyLeftShift,
+		glfw.KeyLeftControl:  imgui.KeyLeftCtrl,
+		glfw.KeyLeftAlt:      imgui.KeyLeftAlt,
+		glfw.KeyLeftSuper:    imgui.KeyLeftSuper,
+		glfw.KeyRightShift:   imgui.KeyRightShift,
+		glfw.KeyRightControl: imgui.KeyRightCtrl,
+		glfw.KeyRightAlt:     imgui.KeyRightAlt,
+		glfw.KeyRightSuper:   imgui.KeyRightSuper,
+		glfw.KeyMenu:         imgui.KeyMenu,
+	}
+	for offset := 0; offset < 10; offset++ {
+		platform.keyMap[glfw.Key0+glfw.Key(offset)] = imgui.Key0 + imgui.Key(offset)
+		platform.keyMap[glfw.KeyKP0+glfw.Key(offset)] = imgui.KeyKeypad0 + imgui.Key(offset)
+	}
+	for offset := 0; offset < 26; offset++ {
+		platform.keyMap[glfw.KeyA+glfw.Key(offset)] = imgui.KeyA + imgui.Key(offset)
 	}
+	for offset := 0; offset < 12; offset++ {
+		platform.keyMap[glfw.KeyF1+glfw.Key(offset)] = imgui.KeyF1 + imgui.Key(offset)
+	}
+}
+
+func setClientAPIHints(clientAPI GLFWClientAPI) bool {
+	switch clientAPI {
+	case GLFWClientAPIOpenGL2:
+		glfw.WindowHint(glfw.ContextVersionMajor, 2)
+		glfw.WindowHint(glfw.ContextVersionMinor, 1)
+	case GLFWClientAPIOpenGL3:
+		glfw.WindowHint(glfw.ContextVersionMajor, 3)
+		glfw.WindowHint(glfw.ContextVersionMinor, 2)
+		glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
+		glfw.WindowHint(glfw.OpenGLForwardCompatible, 1)
+	default:
+		return false
+	}
+	return true
+}
 
+func (platform *GLFW) applyClientAPIHints() {
+	setClientAPIHints(platform.clientAPI)
 }
 
 func (platform *GLFW) installCallbacks() {
-	platform.window.SetMouseButtonCallback(platform.mouseButtonChange)
-	platform.window.SetScrollCallback(platform.mouseScrollChange)
-	platform.window.SetKeyCallback(platform.keyChange)
-	platform.window.SetCharCallback(platform.charChange)
+	platform.installInputCallbacks(platform.window)
+	platform.window.SetContentScaleCallback(platform.contentScaleChange)
+	platform.window.SetPosCallback(platform.windowPosChange)
+	platform.window.SetSizeCallback(platform.windowSizeChange)
+	platform.window.SetFocusCallback(platform.focusChange)
+	platform.window.SetCloseCallback(platform.closeRequest)
+	platform.window.SetRefreshCallback(platform.refreshRequest)
+}
+
+func (platform *GLFW) installInputCallbacks(window *glfw.Window) {
+	window.SetMouseButtonCallback(platform.mouseButtonChange)
+	window.SetCursorPosCallback(platform.mouseMove)
+	window.SetCursorEnterCallback(platform.mouseEnter)
+	window.SetScrollCallback(platform.mouseScrollChange)
+	window.SetKeyCallback(platform.keyChange)
+	window.SetCharCallback(platform.charChange)
+	window.SetDropCallback(platform.fileDrop)
 }
 
 var glfwButtonIndexByID = map[glfw.MouseButton]int{
@@ -181,36 +334,89 @@ var glfwButtonIndexByID = map[glfw.MouseButton]int{
 	glfw.MouseButton3: mouseButtonTertiary,
 }
 
-var glfwButtonIDByIndex = map[int]glfw.MouseButton{
-	mouseButtonPrimary:   glfw.MouseButton1,
-	mouseButtonSecondary: glfw.MouseButton2,
-	mouseButtonTertiary:  glfw.MouseButton3,
+var glfwActions = map[glfw.Action]events.Action{
+	glfw.Release: events.ActionRelease,
+	glfw.Press:   events.ActionPress,
+	glfw.Repeat:  events.ActionRepeat,
+}
+
+func modifiers(mods glfw.ModifierKey) events.Modifiers {
+	var result events.Modifiers
+	if (mods & glfw.ModShift) != 0 {
+		result |= events.ModShift
+	}
+	if (mods & glfw.ModControl) != 0 {
+		result |= events.ModCtrl
+	}
+	if (mods & glfw.ModAlt) != 0 {
+		result |= events.ModAlt
+	}
+	if (mods & glfw.ModSuper) != 0 {
+		result |= events.ModSuper
+	}
+	return result
 }
 
 func (platform *GLFW) mouseButtonChange(window *glfw.Window, rawButton glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
 	buttonIndex, known := glfwButtonIndexByID[rawButton]
+	if !known {
+		return
+	}
+	platform.emit(events.MouseButton{Button: buttonIndex, Action: glfwActions[action], Mods: modifiers(mods)})
+}
+
+func (platform *GLFW) mouseMove(window *glfw.Window, x, y float64) {
+	platform.emit(events.MouseMove{Pos: platform.mousePos(window, x, y)})
+}
+
+func (platform *GLFW) mouseEnter(window *glfw.Window, entered bool) {
+	if !entered {
+		platform.emit(events.MouseMove{Pos: imgui.Vec2{X: -math.MaxFloat32, Y: -math.MaxFloat32}})
+	}
+}
 
-	if known && (action == glfw.Press) {
-		platform.mouseJustPressed[buttonIndex] = true
+// mousePos converts a cursor position within a window to imgui coordinates.
+// With viewports enabled, imgui expects the mouse position in absolute coordinates of the desktop.
+func (platform *GLFW) mousePos(window *glfw.Window, x, y float64) imgui.Vec2 {
+	if platform.viewportsEnabled() {
+		windowX, windowY := window.GetPos()
+		x, y = x+float64(windowX), y+float64(windowY)
 	}
+	return imgui.Vec2{X: float32(x), Y: float32(y)}
 }
 
 func (platform *GLFW) mouseScrollChange(window *glfw.Window, x, y float64) {
-	platform.imguiIO.AddMouseWheelDelta(float32(x), float32(y))
+	platform.emit(events.Scroll{X: float32(x), Y: float32(y)})
 }
 
 func (platform *GLFW) keyChange(window *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
-	imKey := imgui.Key(key)
-	if mapped, ok := platform.keyMap[key]; ok {
-		imKey = mapped
-	}
-
-	platform.imguiIO.AddKeyEvent(imKey, action == glfw.Press)
+	platform.emit(events.Key{
+		Key:      platform.keyMap[key],
+		Scancode: scancode,
+		Action:   glfwActions[action],
+		Mods:     modifiers(mods),
+	})
 }
 
 func (platform *GLFW) charChange(window *glfw.Window, char rune) {
-	platform.imguiIO.AddInputCharactersUTF8(string(char))
-	//platform.imguiIO.AddInputCharacters(string(char))
+	platform.emit(events.Char{Char: char})
+}
+
+func (platform *GLFW) focusChange(window *glfw.Window, focused bool) {
+	platform.emit(events.Focus{Focused: focused})
+}
+
+func (platform *GLFW) closeRequest(window *glfw.Window) {
+	window.SetShouldClose(false)
+	platform.emit(events.Close{})
+}
+
+func (platform *GLFW) refreshRequest(window *glfw.Window) {
+	platform.emit(events.Refresh{})
+}
+
+func (platform *GLFW) contentScaleChange(window *glfw.Window, x, y float32) {
+	platform.contentScale = x
 }
 
 // ClipboardText returns the current clipboard text, if available.
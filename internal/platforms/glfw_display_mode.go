@@ -0,0 +1,97 @@
+package platforms
+
+import (
+	"github.com/go-gl/glfw/v3.3/glfw"
+
+	"github.com/ptxmac/cimgui-go-examples/internal/display"
+)
+
+// Monitors returns the connected monitors, with the primary monitor first.
+func (platform *GLFW) Monitors() []display.Monitor {
+	var monitors []display.Monitor
+	for index, monitor := range glfw.GetMonitors() {
+		current := monitor.GetVideoMode()
+		if current == nil {
+			continue
+		}
+		x, y := monitor.GetPos()
+		entry := display.Monitor{
+			Name:    monitor.GetName(),
+			Primary: index == 0,
+			X:       x,
+			Y:       y,
+			Current: videoMode(current),
+		}
+		for _, mode := range monitor.GetVideoModes() {
+			entry.Modes = append(entry.Modes, videoMode(mode))
+		}
+		monitors = append(monitors, entry)
+	}
+	return monitors
+}
+
+// DisplayMode returns the current mode, and the name of the monitor the window is on.
+func (platform *GLFW) DisplayMode() (display.Mode, string) {
+	var name string
+	if monitor := platform.currentMonitor(); monitor != nil {
+		name = monitor.GetName()
+	}
+	return platform.displayMode, name
+}
+
+// SetDisplayMode switches to the mode on the named monitor. The video mode only applies to display.ModeFullscreen;
+// its zero value keeps the current video mode of the monitor. Switching to display.ModeWindowed restores the
+// geometry, including the maximized state, the window had before it left the windowed mode.
+func (platform *GLFW) SetDisplayMode(mode display.Mode, monitorName string, video display.VideoMode) error {
+	window := platform.window
+	if mode == display.ModeWindowed {
+		if platform.displayMode == display.ModeWindowed {
+			return nil
+		}
+		platform.displayMode = mode
+		geometry := platform.windowed
+		window.SetAttrib(glfw.Decorated, glfw.True)
+		window.SetMonitor(nil, geometry.X, geometry.Y, geometry.Width, geometry.Height, glfw.DontCare)
+		if platform.windowedMaximized {
+			window.Maximize()
+		}
+		return nil
+	}
+
+	if (mode != display.ModeBorderless) && (mode != display.ModeFullscreen) {
+		return ErrUnsupportedDisplayMode
+	}
+	monitor := findMonitor(monitorName)
+	if (monitor == nil) || (monitor.GetName() != monitorName) {
+		return ErrUnknownMonitor
+	}
+	current := monitor.GetVideoMode()
+	if current == nil {
+		return ErrUnknownMonitor
+	}
+	if platform.displayMode == display.ModeWindowed {
+		platform.trackWindowedGeometry()
+		platform.windowedMaximized = window.GetAttrib(glfw.Maximized) != 0
+		window.Restore()
+	}
+	platform.displayMode = mode
+
+	if mode == display.ModeBorderless {
+		x, y := monitor.GetPos()
+		window.SetMonitor(nil, x, y, current.Width, current.Height, glfw.DontCare)
+		window.SetAttrib(glfw.Decorated, glfw.False)
+		// Some window managers adjust the geometry when the decoration is removed.
+		window.SetPos(x, y)
+		window.SetSize(current.Width, current.Height)
+	} else {
+		if video == (display.VideoMode{}) {
+			video = videoMode(current)
+		}
+		window.SetMonitor(monitor, 0, 0, video.Width, video.Height, video.RefreshRate)
+	}
+	return nil
+}
+
+func videoMode(mode *glfw.VidMode) display.VideoMode {
+	return display.VideoMode{Width: mode.Width, Height: mode.Height, RefreshRate: mode.RefreshRate}
+}
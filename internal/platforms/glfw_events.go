@@ -0,0 +1,35 @@
+package platforms
+
+import (
+	"github.com/go-gl/glfw/v3.3/glfw"
+
+	"github.com/ptxmac/cimgui-go-examples/internal/events"
+)
+
+// Events returns the events that occurred since the last call, in the order they occurred.
+// The events are not forwarded to imgui by the platform; pass them to events.Forward for that.
+// The events following a drop are held back for the next call, so that the drop is the last one of its frame
+// and imgui tests the hover state of drop targets at its position.
+func (platform *GLFW) Events() []events.Event {
+	count := len(platform.events)
+	for index, event := range platform.events {
+		if _, isDrop := event.(events.Drop); isDrop {
+			count = index + 1
+			break
+		}
+	}
+	result := platform.events[:count:count]
+	platform.events = append([]events.Event(nil), platform.events[count:]...)
+	return result
+}
+
+func (platform *GLFW) emit(event events.Event) {
+	platform.events = append(platform.events, event)
+}
+
+func (platform *GLFW) fileDrop(window *glfw.Window, names []string) {
+	x, y := window.GetCursorPos()
+	pos := platform.mousePos(window, x, y)
+	platform.emit(events.MouseMove{Pos: pos})
+	platform.emit(events.Drop{Paths: names, Pos: pos})
+}
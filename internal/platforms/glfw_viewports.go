@@ -0,0 +1,308 @@
+package platforms
+
+// #include "viewports.h"
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/AllenDang/cimgui-go"
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+// WindowRenderer draws the content of secondary viewports.
+type WindowRenderer interface {
+	// RenderWindow draws the draw data of the viewport into the current context.
+	RenderWindow(viewport imgui.Viewport)
+}
+
+// viewportPlatform is the platform that serves the viewport callbacks of imgui.
+// A cimgui context supports only one platform, so one instance suffices.
+var viewportPlatform *GLFW
+
+// viewportWindow is the GLFW window of a viewport.
+type viewportWindow struct {
+	window *glfw.Window
+	owned  bool
+
+	// ignorePosFrame and ignoreSizeFrame identify the frame in which imgui moved or resized the window itself.
+	// The resulting events are not reported back as requests of the user.
+	ignorePosFrame  int
+	ignoreSizeFrame int
+}
+
+// SetWindowRenderer sets the renderer for the content of secondary viewports.
+// Secondary viewports are created if imgui.ConfigFlagsViewportsEnable is set in the imgui IO.
+// Their windows share the OpenGL context of the main window.
+func (platform *GLFW) SetWindowRenderer(renderer WindowRenderer) {
+	platform.windowRenderer = renderer
+}
+
+func (platform *GLFW) installViewportCallbacks() {
+	viewportPlatform = platform
+	platform.viewports = map[imgui.ID]*viewportWindow{
+		imgui.MainViewport().ID(): {window: platform.window},
+	}
+	C.installViewportCallbacks(currentPlatformIO())
+	platform.imguiIO.SetBackendFlags(platform.imguiIO.BackendFlags() | imgui.BackendFlagsPlatformHasViewports)
+
+	platform.monitorsChanged = true
+	glfw.SetMonitorCallback(func(*glfw.Monitor, glfw.PeripheralEvent) {
+		platform.monitorsChanged = true
+	})
+}
+
+func (platform *GLFW) removeViewportCallbacks() {
+	imgui.DestroyPlatformWindows()
+	glfw.SetMonitorCallback(nil)
+	C.removeViewportCallbacks(currentPlatformIO())
+	platform.viewports = nil
+	viewportPlatform = nil
+}
+
+// Viewports returns all viewports of imgui, starting with the main viewport.
+// The list includes viewports for which no window was created yet.
+func (platform *GLFW) Viewports() []imgui.Viewport {
+	vector := currentPlatformIO().Viewports
+	if vector.Size == 0 {
+		return []imgui.Viewport{imgui.MainViewport()}
+	}
+	pointers := unsafe.Slice(vector.Data, vector.Size)
+	viewports := make([]imgui.Viewport, len(pointers))
+	for i, vp := range pointers {
+		viewports[i] = wrapViewport(vp)
+	}
+	return viewports
+}
+
+func (platform *GLFW) viewportsEnabled() bool {
+	return (platform.imguiIO.ConfigFlags() & imgui.ConfigFlagsViewportsEnable) != 0
+}
+
+// updateMonitors provides the current list of monitors to imgui.
+// imgui uses them to keep viewports on visible areas and to determine their DPI scale.
+func (platform *GLFW) updateMonitors() {
+	if !platform.monitorsChanged {
+		return
+	}
+	platform.monitorsChanged = false
+
+	monitors := glfw.GetMonitors()
+	platformIO := currentPlatformIO()
+	if platformIO.Monitors.Data != nil {
+		imgui.MemFree(unsafe.Pointer(platformIO.Monitors.Data))
+	}
+	platformIO.Monitors = C.ImVector_ImGuiPlatformMonitor{}
+	if len(monitors) == 0 {
+		return
+	}
+
+	var entry C.ImGuiPlatformMonitor
+	data := imgui.MemAlloc(uint64(len(monitors)) * uint64(unsafe.Sizeof(entry)))
+	entries := unsafe.Slice((*C.ImGuiPlatformMonitor)(data), len(monitors))
+	count := 0
+	for _, monitor := range monitors {
+		mode := monitor.GetVideoMode()
+		if mode == nil {
+			continue
+		}
+		x, y := monitor.GetPos()
+		workX, workY, workWidth, workHeight := monitor.GetWorkarea()
+		scale, _ := monitor.GetContentScale()
+		entries[count] = C.ImGuiPlatformMonitor{
+			MainPos:  vec2(float32(x), float32(y)),
+			MainSize: vec2(float32(mode.Width), float32(mode.Height)),
+			WorkPos:  vec2(float32(workX), float32(workY)),
+			WorkSize: vec2(float32(workWidth), float32(workHeight)),
+			DpiScale: C.float(scale),
+		}
+		count++
+	}
+	platformIO.Monitors = C.ImVector_ImGuiPlatformMonitor{
+		Size:     C.int(count),
+		Capacity: C.int(len(monitors)),
+		Data:     (*C.ImGuiPlatformMonitor)(data),
+	}
+}
+
+func (platform *GLFW) createViewportWindow(viewport imgui.Viewport) {
+	flags := viewport.Flags()
+	glfw.WindowHint(glfw.Visible, glfw.False)
+	glfw.WindowHint(glfw.Focused, glfw.False)
+	glfw.WindowHint(glfw.FocusOnShow, glfw.False)
+	glfw.WindowHint(glfw.Decorated, glfwBool((flags&imgui.ViewportFlagsNoDecoration) == 0))
+	glfw.WindowHint(glfw.Floating, glfwBool((flags&imgui.ViewportFlagsTopMost) != 0))
+	size := viewport.Size()
+	window, err := glfw.CreateWindow(int(size.X), int(size.Y), "No Title Yet", nil, platform.window)
+	glfw.DefaultWindowHints()
+	platform.applyClientAPIHints()
+	if err != nil {
+		return
+	}
+	pos := viewport.Pos()
+	window.SetPos(int(pos.X), int(pos.Y))
+
+	entry := &viewportWindow{window: window, owned: true}
+	platform.viewports[viewport.ID()] = entry
+	platform.installInputCallbacks(window)
+
+	id := viewport.ID()
+	window.SetCloseCallback(func(*glfw.Window) {
+		imgui.FindViewportByID(id).SetPlatformRequestClose(true)
+	})
+	window.SetPosCallback(func(*glfw.Window, int, int) {
+		if entry.ignorePosFrame != imgui.FrameCount() {
+			imgui.FindViewportByID(id).SetPlatformRequestMove(true)
+		}
+	})
+	window.SetSizeCallback(func(*glfw.Window, int, int) {
+		if entry.ignoreSizeFrame != imgui.FrameCount() {
+			imgui.FindViewportByID(id).SetPlatformRequestResize(true)
+		}
+	})
+	window.SetRefreshCallback(platform.refreshRequest)
+	window.MakeContextCurrent()
+	glfw.SwapInterval(0)
+}
+
+func (platform *GLFW) destroyViewportWindow(viewport imgui.Viewport) {
+	entry, known := platform.viewports[viewport.ID()]
+	if !known {
+		return
+	}
+	if entry.owned {
+		entry.window.Destroy()
+	}
+	delete(platform.viewports, viewport.ID())
+}
+
+func (platform *GLFW) viewportWindow(vp *C.ImGuiViewport) *viewportWindow {
+	return platform.viewports[wrapViewport(vp).ID()]
+}
+
+func currentPlatformIO() *C.ImGuiPlatformIO {
+	return (*C.ImGuiPlatformIO)(unsafe.Pointer(imgui.CurrentPlatformIO()))
+}
+
+func wrapViewport(vp *C.ImGuiViewport) imgui.Viewport {
+	return imgui.Viewport(uintptr(unsafe.Pointer(vp)))
+}
+
+func vec2(x, y float32) C.ImVec2 {
+	return C.ImVec2{x: C.float(x), y: C.float(y)}
+}
+
+func glfwBool(value bool) int {
+	if value {
+		return glfw.True
+	}
+	return glfw.False
+}
+
+//export glfwViewportCreateWindow
+func glfwViewportCreateWindow(vp *C.ImGuiViewport) {
+	viewportPlatform.createViewportWindow(wrapViewport(vp))
+}
+
+//export glfwViewportDestroyWindow
+func glfwViewportDestroyWindow(vp *C.ImGuiViewport) {
+	viewportPlatform.destroyViewportWindow(wrapViewport(vp))
+}
+
+//export glfwViewportShowWindow
+func glfwViewportShowWindow(vp *C.ImGuiViewport) {
+	if entry := viewportPlatform.viewportWindow(vp); entry != nil {
+		entry.window.Show()
+	}
+}
+
+//export glfwViewportSetWindowPos
+func glfwViewportSetWindowPos(vp *C.ImGuiViewport, pos C.ImVec2) {
+	if entry := viewportPlatform.viewportWindow(vp); entry != nil {
+		entry.ignorePosFrame = imgui.FrameCount()
+		entry.window.SetPos(int(pos.x), int(pos.y))
+	}
+}
+
+//export glfwViewportGetWindowPos
+func glfwViewportGetWindowPos(vp *C.ImGuiViewport) C.ImVec2 {
+	entry := viewportPlatform.viewportWindow(vp)
+	if entry == nil {
+		return vec2(0, 0)
+	}
+	x, y := entry.window.GetPos()
+	return vec2(float32(x), float32(y))
+}
+
+//export glfwViewportSetWindowSize
+func glfwViewportSetWindowSize(vp *C.ImGuiViewport, size C.ImVec2) {
+	if entry := viewportPlatform.viewportWindow(vp); entry != nil {
+		entry.ignoreSizeFrame = imgui.FrameCount()
+		entry.window.SetSize(int(size.x), int(size.y))
+	}
+}
+
+//export glfwViewportGetWindowSize
+func glfwViewportGetWindowSize(vp *C.ImGuiViewport) C.ImVec2 {
+	entry := viewportPlatform.viewportWindow(vp)
+	if entry == nil {
+		return vec2(0, 0)
+	}
+	width, height := entry.window.GetSize()
+	return vec2(float32(width), float32(height))
+}
+
+//export glfwViewportSetWindowFocus
+func glfwViewportSetWindowFocus(vp *C.ImGuiViewport) {
+	if entry := viewportPlatform.viewportWindow(vp); entry != nil {
+		entry.window.Focus()
+	}
+}
+
+//export glfwViewportGetWindowFocus
+func glfwViewportGetWindowFocus(vp *C.ImGuiViewport) C.bool {
+	entry := viewportPlatform.viewportWindow(vp)
+	return C.bool((entry != nil) && (entry.window.GetAttrib(glfw.Focused) != 0))
+}
+
+//export glfwViewportGetWindowMinimized
+func glfwViewportGetWindowMinimized(vp *C.ImGuiViewport) C.bool {
+	entry := viewportPlatform.viewportWindow(vp)
+	return C.bool((entry != nil) && (entry.window.GetAttrib(glfw.Iconified) != 0))
+}
+
+//export glfwViewportSetWindowTitle
+func glfwViewportSetWindowTitle(vp *C.ImGuiViewport, title *C.char) {
+	if entry := viewportPlatform.viewportWindow(vp); entry != nil {
+		entry.window.SetTitle(C.GoString(title))
+	}
+}
+
+//export glfwViewportSetWindowAlpha
+func glfwViewportSetWindowAlpha(vp *C.ImGuiViewport, alpha C.float) {
+	if entry := viewportPlatform.viewportWindow(vp); entry != nil {
+		entry.window.SetOpacity(float32(alpha))
+	}
+}
+
+//export glfwViewportRenderWindow
+func glfwViewportRenderWindow(vp *C.ImGuiViewport, _ unsafe.Pointer) {
+	if entry := viewportPlatform.viewportWindow(vp); entry != nil {
+		entry.window.MakeContextCurrent()
+	}
+}
+
+//export glfwViewportSwapBuffers
+func glfwViewportSwapBuffers(vp *C.ImGuiViewport, _ unsafe.Pointer) {
+	if entry := viewportPlatform.viewportWindow(vp); entry != nil {
+		entry.window.MakeContextCurrent()
+		entry.window.SwapBuffers()
+	}
+}
+
+//export glfwViewportRendererRenderWindow
+func glfwViewportRendererRenderWindow(vp *C.ImGuiViewport, _ unsafe.Pointer) {
+	if viewportPlatform.windowRenderer != nil {
+		viewportPlatform.windowRenderer.RenderWindow(wrapViewport(vp))
+	}
+}
@@ -0,0 +1,32 @@
+package platforms
+
+import (
+	"image"
+)
+
+// Title returns the current title of the main window.
+func (platform *GLFW) Title() string {
+	return platform.title
+}
+
+// SetTitle changes the title of the main window.
+func (platform *GLFW) SetTitle(title string) {
+	if title == platform.title {
+		return
+	}
+	platform.title = title
+	platform.window.SetTitle(title)
+}
+
+// SetIcon sets the icon of the main window. The images should be of different sizes, such as 16x16, 32x32 and 48x48;
+// the system picks the size closest to the one it needs. Without images, the default icon is restored.
+// On macOS, windows have no icons and the call is ignored.
+func (platform *GLFW) SetIcon(images []image.Image) {
+	platform.window.SetIcon(images)
+}
+
+// RequestAttention highlights the main window, for example by flashing its entry in the task bar,
+// if it does not have the focus. The highlight ends when the user activates the window.
+func (platform *GLFW) RequestAttention() {
+	platform.window.RequestAttention()
+}
@@ -0,0 +1,164 @@
+package platforms
+
+import (
+	"github.com/AllenDang/cimgui-go"
+	"github.com/go-gl/glfw/v3.3/glfw"
+
+	"github.com/ptxmac/cimgui-go-examples/internal/display"
+	"github.com/ptxmac/cimgui-go-examples/internal/events"
+	"github.com/ptxmac/cimgui-go-examples/internal/settings"
+)
+
+// windowStateKey is the section of the settings that holds the window state.
+const windowStateKey = "window"
+
+// minVisibleWidth and minVisibleHeight are the minimal extents of a restored window
+// that must be within the work area of a monitor for the window to be reachable.
+const (
+	minVisibleWidth  = 64
+	minVisibleHeight = 32
+)
+
+// WindowState describes the geometry of the main window, to be restored in a later session.
+type WindowState struct {
+	// X, Y, Width and Height describe the window while it is neither maximized nor fullscreen, in screen coordinates.
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+
+	Maximized  bool `json:"maximized"`
+	Fullscreen bool `json:"fullscreen"`
+	Borderless bool `json:"borderless"`
+	// Monitor is the name of the monitor the window was on.
+	Monitor string `json:"monitor"`
+}
+
+// WindowState returns the current geometry of the main window.
+func (platform *GLFW) WindowState() WindowState {
+	state := WindowState{
+		X:          platform.windowed.X,
+		Y:          platform.windowed.Y,
+		Width:      platform.windowed.Width,
+		Height:     platform.windowed.Height,
+		Maximized:  platform.window.GetAttrib(glfw.Maximized) != 0,
+		Fullscreen: platform.displayMode == display.ModeFullscreen,
+		Borderless: platform.displayMode == display.ModeBorderless,
+	}
+	if platform.displayMode != display.ModeWindowed {
+		state.Maximized = platform.windowedMaximized
+	}
+	if monitor := platform.currentMonitor(); monitor != nil {
+		state.Monitor = monitor.GetName()
+	}
+	return state
+}
+
+// RestoreWindowState applies a geometry previously returned by WindowState.
+// If the window would not be visible, for example because its monitor has been disconnected since,
+// it is moved into the work area of its monitor, or the primary monitor.
+func (platform *GLFW) RestoreWindowState(state WindowState) {
+	if (state.Width <= 0) || (state.Height <= 0) {
+		return
+	}
+	monitor := findMonitor(state.Monitor)
+	state = clampWindowState(state, monitor)
+
+	_ = platform.SetDisplayMode(display.ModeWindowed, "", display.VideoMode{})
+	platform.window.Restore()
+	platform.window.SetPos(state.X, state.Y)
+	platform.window.SetSize(state.Width, state.Height)
+	platform.windowed = state
+	if state.Maximized {
+		platform.window.Maximize()
+	}
+
+	if monitor != nil {
+		switch {
+		case state.Fullscreen:
+			_ = platform.SetDisplayMode(display.ModeFullscreen, monitor.GetName(), display.VideoMode{})
+		case state.Borderless:
+			_ = platform.SetDisplayMode(display.ModeBorderless, monitor.GetName(), display.VideoMode{})
+		}
+	}
+}
+
+// LoadWindowState restores the window state from the settings, if they contain one.
+func (platform *GLFW) LoadWindowState(store *settings.Store) error {
+	var state WindowState
+	found, err := store.Get(windowStateKey, &state)
+	if found && (err == nil) {
+		platform.RestoreWindowState(state)
+	}
+	return err
+}
+
+// SaveWindowState puts the current window state into the settings.
+func (platform *GLFW) SaveWindowState(store *settings.Store) error {
+	return store.Set(windowStateKey, platform.WindowState())
+}
+
+// trackWindowedGeometry remembers the geometry of the window while it is neither maximized, minimized nor fullscreen.
+func (platform *GLFW) trackWindowedGeometry() {
+	window := platform.window
+	if (platform.displayMode != display.ModeWindowed) || (window.GetAttrib(glfw.Maximized) != 0) || (window.GetAttrib(glfw.Iconified) != 0) {
+		return
+	}
+	platform.windowed.X, platform.windowed.Y = window.GetPos()
+	platform.windowed.Width, platform.windowed.Height = window.GetSize()
+}
+
+func (platform *GLFW) windowPosChange(window *glfw.Window, x, y int) {
+	platform.trackWindowedGeometry()
+}
+
+func (platform *GLFW) windowSizeChange(window *glfw.Window, width, height int) {
+	platform.trackWindowedGeometry()
+	platform.emit(events.Resize{Size: imgui.Vec2{X: float32(width), Y: float32(height)}})
+}
+
+// findMonitor returns the connected monitor of given name, or the primary monitor.
+func findMonitor(name string) *glfw.Monitor {
+	for _, monitor := range glfw.GetMonitors() {
+		if monitor.GetName() == name {
+			return monitor
+		}
+	}
+	return glfw.GetPrimaryMonitor()
+}
+
+// clampWindowState moves the window into the work area of the monitor unless it is sufficiently visible on any monitor.
+func clampWindowState(state WindowState, monitor *glfw.Monitor) WindowState {
+	for _, candidate := range glfw.GetMonitors() {
+		x, y, width, height := candidate.GetWorkarea()
+		visibleWidth := minInt(state.X+state.Width, x+width) - maxInt(state.X, x)
+		visibleHeight := minInt(state.Y+state.Height, y+height) - maxInt(state.Y, y)
+		// The title bar must not be above the work area, or the window could not be moved anymore.
+		if (visibleWidth >= minVisibleWidth) && (visibleHeight >= minVisibleHeight) && (state.Y >= y) {
+			return state
+		}
+	}
+	if monitor == nil {
+		return state
+	}
+	x, y, width, height := monitor.GetWorkarea()
+	state.Width = minInt(state.Width, width)
+	state.Height = minInt(state.Height, height)
+	state.X = maxInt(x, minInt(state.X, x+width-state.Width))
+	state.Y = maxInt(y, minInt(state.Y, y+height-state.Height))
+	return state
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,42 @@
+#include "viewports.h"
+#include "_cgo_export.h"
+
+static void setWindowTitle(ImGuiViewport *vp, const char *title) {
+	glfwViewportSetWindowTitle(vp, (char *)title);
+}
+
+void installViewportCallbacks(ImGuiPlatformIO *io) {
+	io->Platform_CreateWindow = glfwViewportCreateWindow;
+	io->Platform_DestroyWindow = glfwViewportDestroyWindow;
+	io->Platform_ShowWindow = glfwViewportShowWindow;
+	io->Platform_SetWindowPos = glfwViewportSetWindowPos;
+	io->Platform_GetWindowPos = glfwViewportGetWindowPos;
+	io->Platform_SetWindowSize = glfwViewportSetWindowSize;
+	io->Platform_GetWindowSize = glfwViewportGetWindowSize;
+	io->Platform_SetWindowFocus = glfwViewportSetWindowFocus;
+	io->Platform_GetWindowFocus = glfwViewportGetWindowFocus;
+	io->Platform_GetWindowMinimized = glfwViewportGetWindowMinimized;
+	io->Platform_SetWindowTitle = setWindowTitle;
+	io->Platform_SetWindowAlpha = glfwViewportSetWindowAlpha;
+	io->Platform_RenderWindow = glfwViewportRenderWindow;
+	io->Platform_SwapBuffers = glfwViewportSwapBuffers;
+	io->Renderer_RenderWindow = glfwViewportRendererRenderWindow;
+}
+
+void removeViewportCallbacks(ImGuiPlatformIO *io) {
+	io->Platform_CreateWindow = 0;
+	io->Platform_DestroyWindow = 0;
+	io->Platform_ShowWindow = 0;
+	io->Platform_SetWindowPos = 0;
+	io->Platform_GetWindowPos = 0;
+	io->Platform_SetWindowSize = 0;
+	io->Platform_GetWindowSize = 0;
+	io->Platform_SetWindowFocus = 0;
+	io->Platform_GetWindowFocus = 0;
+	io->Platform_GetWindowMinimized = 0;
+	io->Platform_SetWindowTitle = 0;
+	io->Platform_SetWindowAlpha = 0;
+	io->Platform_RenderWindow = 0;
+	io->Platform_SwapBuffers = 0;
+	io->Renderer_RenderWindow = 0;
+}
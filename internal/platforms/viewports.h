@@ -0,0 +1,56 @@
+// Declarations of the imgui types that are needed to serve the platform callbacks for viewports.
+// They mirror the layout of cimgui.h of the imgui version used by github.com/AllenDang/cimgui-go,
+// which does not provide access to the callbacks of ImGuiPlatformIO itself.
+#ifndef PLATFORMS_VIEWPORTS_H
+#define PLATFORMS_VIEWPORTS_H
+
+#include <stdbool.h>
+#include <stdint.h>
+
+typedef struct ImVec2 { float x, y; } ImVec2;
+typedef struct ImGuiViewport ImGuiViewport;
+
+typedef struct ImGuiPlatformMonitor {
+	ImVec2 MainPos, MainSize;
+	ImVec2 WorkPos, WorkSize;
+	float DpiScale;
+	void *PlatformHandle;
+} ImGuiPlatformMonitor;
+
+typedef struct ImVector_ImGuiPlatformMonitor { int Size; int Capacity; ImGuiPlatformMonitor *Data; } ImVector_ImGuiPlatformMonitor;
+typedef struct ImVector_ImGuiViewportPtr { int Size; int Capacity; ImGuiViewport **Data; } ImVector_ImGuiViewportPtr;
+
+typedef struct ImGuiPlatformIO {
+	void (*Platform_CreateWindow)(ImGuiViewport *vp);
+	void (*Platform_DestroyWindow)(ImGuiViewport *vp);
+	void (*Platform_ShowWindow)(ImGuiViewport *vp);
+	void (*Platform_SetWindowPos)(ImGuiViewport *vp, ImVec2 pos);
+	ImVec2 (*Platform_GetWindowPos)(ImGuiViewport *vp);
+	void (*Platform_SetWindowSize)(ImGuiViewport *vp, ImVec2 size);
+	ImVec2 (*Platform_GetWindowSize)(ImGuiViewport *vp);
+	void (*Platform_SetWindowFocus)(ImGuiViewport *vp);
+	bool (*Platform_GetWindowFocus)(ImGuiViewport *vp);
+	bool (*Platform_GetWindowMinimized)(ImGuiViewport *vp);
+	void (*Platform_SetWindowTitle)(ImGuiViewport *vp, const char *str);
+	void (*Platform_SetWindowAlpha)(ImGuiViewport *vp, float alpha);
+	void (*Platform_UpdateWindow)(ImGuiViewport *vp);
+	void (*Platform_RenderWindow)(ImGuiViewport *vp, void *render_arg);
+	void (*Platform_SwapBuffers)(ImGuiViewport *vp, void *render_arg);
+	float (*Platform_GetWindowDpiScale)(ImGuiViewport *vp);
+	void (*Platform_OnChangedViewport)(ImGuiViewport *vp);
+	int (*Platform_CreateVkSurface)(ImGuiViewport *vp, uint64_t vk_inst, const void *vk_allocators, uint64_t *out_vk_surface);
+	void (*Renderer_CreateWindow)(ImGuiViewport *vp);
+	void (*Renderer_DestroyWindow)(ImGuiViewport *vp);
+	void (*Renderer_SetWindowSize)(ImGuiViewport *vp, ImVec2 size);
+	void (*Renderer_RenderWindow)(ImGuiViewport *vp, void *render_arg);
+	void (*Renderer_SwapBuffers)(ImGuiViewport *vp, void *render_arg);
+	ImVector_ImGuiPlatformMonitor Monitors;
+	ImVector_ImGuiViewportPtr Viewports;
+} ImGuiPlatformIO;
+
+// installViewportCallbacks sets the callbacks of the platform IO to the functions exported by the Go code.
+void installViewportCallbacks(ImGuiPlatformIO *io);
+// removeViewportCallbacks resets the callbacks of the platform IO.
+void removeViewportCallbacks(ImGuiPlatformIO *io);
+
+#endif
@@ -0,0 +1,42 @@
+package profiler
+
+// Phase is a section of the frame loop that is timed separately.
+type Phase int
+
+// This is a list of Phase constants, in the order they occur in a frame.
+const (
+	// PhaseEvents covers processing the events of the platform and running posted tasks.
+	PhaseEvents Phase = iota
+	// PhaseNewFrame covers preparing the platform, the fonts and the renderer, up to imgui.NewFrame().
+	PhaseNewFrame
+	// PhaseLayout covers building the user interface of the application.
+	PhaseLayout
+	// PhaseImguiRender covers imgui.Render(), which produces the draw data.
+	PhaseImguiRender
+	// PhaseRender covers rendering the draw data of all viewports.
+	PhaseRender
+	// PhasePresent covers the buffer swap of the platform.
+	PhasePresent
+	// PhaseSleep covers waiting for the next frame.
+	PhaseSleep
+
+	phaseCount
+)
+
+var phaseNames = [phaseCount]string{
+	PhaseEvents:      "Events",
+	PhaseNewFrame:    "New frame",
+	PhaseLayout:      "Layout",
+	PhaseImguiRender: "imgui.Render",
+	PhaseRender:      "Render",
+	PhasePresent:     "Present",
+	PhaseSleep:       "Sleep",
+}
+
+// String returns a short name of the phase.
+func (phase Phase) String() string {
+	if (phase < 0) || (phase >= phaseCount) {
+		return "Unknown"
+	}
+	return phaseNames[phase]
+}
@@ -0,0 +1,236 @@
+package profiler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AllenDang/cimgui-go"
+)
+
+const (
+	overlayName    = "##profiler"
+	overlayPadding = 10
+	overlayAlpha   = 0.8
+	graphWidth     = 24 // in multiples of the font size
+	graphHeight    = 4
+	// minGraphScale keeps the graph of fast frames from exaggerating small variations.
+	minGraphScale = 1000.0 / 30.0
+	floatStride   = 4
+)
+
+// Profiler measures the frames of the frame loop, and shows the measurements in an overlay.
+// The frame loop calls BeginFrame, then Begin for each following phase, and EndFrame.
+// A Profiler must only be used from the thread that runs the frame loop.
+type Profiler struct {
+	hotkey  imgui.Key
+	visible bool
+
+	history []Sample
+	next    int
+	count   int
+
+	current    Sample
+	phase      Phase
+	frameStart time.Time
+	phaseStart time.Time
+	gc         *gcPauses
+
+	// values and durations are reused for the graphs and the statistics.
+	values    []float32
+	durations []time.Duration
+}
+
+// NewProfiler returns a profiler that keeps the measurements of given number of frames.
+// The hotkey toggles the overlay, imgui.KeyF12 is common.
+func NewProfiler(historySize int, hotkey imgui.Key) *Profiler {
+	if historySize < 1 {
+		historySize = 1
+	}
+	return &Profiler{
+		hotkey:  hotkey,
+		history: make([]Sample, historySize),
+		gc:      newGCPauses(),
+	}
+}
+
+// Visible returns true if the overlay is shown.
+func (profiler *Profiler) Visible() bool {
+	return profiler.visible
+}
+
+// SetVisible shows or hides the overlay.
+func (profiler *Profiler) SetVisible(visible bool) {
+	profiler.visible = visible
+}
+
+// BeginFrame starts the measurement of a frame, with PhaseEvents.
+func (profiler *Profiler) BeginFrame() {
+	now := time.Now()
+	profiler.current = Sample{}
+	profiler.phase = PhaseEvents
+	profiler.frameStart = now
+	profiler.phaseStart = now
+}
+
+// Begin ends the current phase and starts the given one. Phases may be entered more than once per frame.
+func (profiler *Profiler) Begin(phase Phase) {
+	now := time.Now()
+	profiler.current.Phases[profiler.phase] += now.Sub(profiler.phaseStart)
+	profiler.phase = phase
+	profiler.phaseStart = now
+}
+
+// CountDrawData records the size of the draw data. It is called after imgui.Render().
+func (profiler *Profiler) CountDrawData(drawData imgui.DrawData) {
+	profiler.current.Vertices = drawData.TotalVtxCount()
+	profiler.current.Indices = drawData.TotalIdxCount()
+	profiler.current.DrawCalls = 0
+	for _, list := range drawData.CommandLists() {
+		profiler.current.DrawCalls += len(list.Commands())
+	}
+}
+
+// EndFrame ends the current phase and adds the measurements of the frame to the history.
+func (profiler *Profiler) EndFrame() {
+	profiler.Begin(profiler.phase)
+	profiler.current.Total = profiler.phaseStart.Sub(profiler.frameStart)
+	profiler.current.GCPauses, profiler.current.GCPauseTime = profiler.gc.read()
+	profiler.history[profiler.next] = profiler.current
+	profiler.next = (profiler.next + 1) % len(profiler.history)
+	if profiler.count < len(profiler.history) {
+		profiler.count++
+	}
+}
+
+// Samples returns the measured frames of the history, the oldest first.
+func (profiler *Profiler) Samples() []Sample {
+	samples := make([]Sample, 0, profiler.count)
+	start := (profiler.next - profiler.count + len(profiler.history)) % len(profiler.history)
+	for i := 0; i < profiler.count; i++ {
+		samples = append(samples, profiler.history[(start+i)%len(profiler.history)])
+	}
+	return samples
+}
+
+// Stats returns the statistics of a measurement over the history, such as Sample.Busy.
+func (profiler *Profiler) Stats(measure func(Sample) time.Duration) Stats {
+	profiler.durations = profiler.durations[:0]
+	for _, sample := range profiler.Samples() {
+		profiler.durations = append(profiler.durations, measure(sample))
+	}
+	return newStats(profiler.durations)
+}
+
+// HandleHotkey toggles the overlay if the hotkey was pressed. It must be called between imgui.NewFrame() and imgui.Render().
+func (profiler *Profiler) HandleHotkey() {
+	if imgui.IsKeyPressedBoolV(profiler.hotkey, false) {
+		profiler.visible = !profiler.visible
+	}
+}
+
+// Overlay shows the measurements in the top right corner of the main viewport, if the overlay is visible.
+func (profiler *Profiler) Overlay() {
+	if !profiler.visible {
+		return
+	}
+	viewport := imgui.MainViewport()
+	workPos, workSize := viewport.WorkPos(), viewport.WorkSize()
+	imgui.SetNextWindowPosV(imgui.Vec2{X: workPos.X + workSize.X - overlayPadding, Y: workPos.Y + overlayPadding},
+		imgui.CondAlways, imgui.Vec2{X: 1, Y: 0})
+	imgui.SetNextWindowViewport(viewport.ID())
+	imgui.SetNextWindowBgAlpha(overlayAlpha)
+	flags := imgui.WindowFlags(imgui.WindowFlagsNoDecoration | imgui.WindowFlagsAlwaysAutoResize | imgui.WindowFlagsNoSavedSettings |
+		imgui.WindowFlagsNoFocusOnAppearing | imgui.WindowFlagsNoNav | imgui.WindowFlagsNoMove | imgui.WindowFlagsNoDocking)
+	if imgui.BeginV(overlayName, &profiler.visible, flags) {
+		profiler.content()
+	}
+	imgui.End()
+}
+
+func (profiler *Profiler) content() {
+	samples := profiler.Samples()
+	if len(samples) == 0 {
+		imgui.TextDisabled("No frames measured yet")
+		return
+	}
+	last := samples[len(samples)-1]
+	imgui.Text(fmt.Sprintf("Frame %.2f ms, busy %.2f ms (%s to toggle)", millis(last.Total), millis(last.Busy()), imgui.KeyName(profiler.hotkey)))
+
+	graphSize := imgui.Vec2{X: imgui.FontSize() * graphWidth, Y: imgui.FontSize() * graphHeight}
+	total := profiler.Stats(frameTime)
+	profiler.graph("##frame", samples, frameTime, "frame time", total.Max, graphSize)
+	busy := profiler.Stats(Sample.Busy)
+	profiler.graph("##busy", samples, Sample.Busy, "busy time", total.Max, graphSize)
+
+	flags := imgui.TableFlags(imgui.TableFlagsRowBg | imgui.TableFlagsSizingFixedFit)
+	if imgui.BeginTableV("##stats", 6, flags, imgui.Vec2{}, 0) {
+		for _, header := range []string{"ms", "mean", "p50", "p95", "p99", "max"} {
+			imgui.TableSetupColumn(header)
+		}
+		imgui.TableHeadersRow()
+		statsRow("Frame", total)
+		statsRow("Busy", busy)
+		imgui.EndTable()
+	}
+
+	if imgui.BeginTableV("##phases", 3, flags, imgui.Vec2{}, 0) {
+		imgui.TableSetupColumn("Phase")
+		imgui.TableSetupColumn("last")
+		imgui.TableSetupColumn("mean")
+		imgui.TableHeadersRow()
+		for phase := PhaseEvents; phase < phaseCount; phase++ {
+			phase := phase
+			mean := profiler.Stats(func(sample Sample) time.Duration { return sample.Phases[phase] }).Mean
+			imgui.TableNextRow()
+			imgui.TableNextColumn()
+			imgui.TextUnformatted(phase.String())
+			imgui.TableNextColumn()
+			imgui.Text(fmt.Sprintf("%.2f", millis(last.Phases[phase])))
+			imgui.TableNextColumn()
+			imgui.Text(fmt.Sprintf("%.2f", millis(mean)))
+		}
+		imgui.EndTable()
+	}
+
+	imgui.Text(fmt.Sprintf("%d vertices, %d indices, %d draw calls", last.Vertices, last.Indices, last.DrawCalls))
+	gcPauses := 0
+	var gcTime, gcMax time.Duration
+	for _, sample := range samples {
+		gcPauses += sample.GCPauses
+		gcTime += sample.GCPauseTime
+		if sample.GCPauseTime > gcMax {
+			gcMax = sample.GCPauseTime
+		}
+	}
+	imgui.Text(fmt.Sprintf("GC: %d pauses, %.2f ms in total, at most %.2f ms per frame", gcPauses, millis(gcTime), millis(gcMax)))
+}
+
+func (profiler *Profiler) graph(id string, samples []Sample, measure func(Sample) time.Duration, label string, max time.Duration, size imgui.Vec2) {
+	profiler.values = profiler.values[:0]
+	for _, sample := range samples {
+		profiler.values = append(profiler.values, millis(measure(sample)))
+	}
+	scale := millis(max)
+	if scale < minGraphScale {
+		scale = minGraphScale
+	}
+	imgui.PlotLinesFloatPtrV(id, profiler.values, int32(len(profiler.values)), 0, label, 0, scale, size, floatStride)
+}
+
+func statsRow(name string, stats Stats) {
+	imgui.TableNextRow()
+	imgui.TableNextColumn()
+	imgui.TextUnformatted(name)
+	for _, value := range []time.Duration{stats.Mean, stats.P50, stats.P95, stats.P99, stats.Max} {
+		imgui.TableNextColumn()
+		imgui.Text(fmt.Sprintf("%.2f", millis(value)))
+	}
+}
+
+func frameTime(sample Sample) time.Duration {
+	return sample.Total
+}
+
+func millis(duration time.Duration) float32 {
+	return float32(duration.Seconds() * 1000)
+}
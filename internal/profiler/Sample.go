@@ -0,0 +1,26 @@
+package profiler
+
+import "time"
+
+// Sample holds the measurements of a single frame.
+type Sample struct {
+	// Phases holds the duration of each phase, indexed by Phase.
+	Phases [phaseCount]time.Duration
+	// Total is the duration of the whole frame, including the sleep.
+	Total time.Duration
+
+	// Vertices, Indices and DrawCalls describe the draw data of the main viewport.
+	Vertices  int
+	Indices   int
+	DrawCalls int
+
+	// GCPauses is the number of pauses of the garbage collector during the frame,
+	// GCPauseTime their estimated total duration.
+	GCPauses    int
+	GCPauseTime time.Duration
+}
+
+// Busy returns the duration of the frame without the sleep.
+func (sample Sample) Busy() time.Duration {
+	return sample.Total - sample.Phases[PhaseSleep]
+}
@@ -0,0 +1,45 @@
+package profiler
+
+import (
+	"sort"
+	"time"
+)
+
+// Stats summarizes a measurement over the frames of the history.
+type Stats struct {
+	Frames int
+	Mean   time.Duration
+	P50    time.Duration
+	P95    time.Duration
+	P99    time.Duration
+	Max    time.Duration
+}
+
+// newStats computes the statistics of the durations. It sorts the durations in place.
+func newStats(durations []time.Duration) Stats {
+	if len(durations) == 0 {
+		return Stats{}
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	var sum time.Duration
+	for _, duration := range durations {
+		sum += duration
+	}
+	return Stats{
+		Frames: len(durations),
+		Mean:   sum / time.Duration(len(durations)),
+		P50:    percentile(durations, 50),
+		P95:    percentile(durations, 95),
+		P99:    percentile(durations, 99),
+		Max:    durations[len(durations)-1],
+	}
+}
+
+// percentile returns the nearest-rank percentile of the sorted durations.
+func percentile(sorted []time.Duration, percent int) time.Duration {
+	rank := (percent*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,4 @@
+// Package profiler measures the phases of each frame, the size of the draw data and the pauses of the
+// garbage collector. It keeps a rolling history of the measurements and shows them in an overlay
+// with a graph of the frame times and percentile statistics.
+package profiler
@@ -0,0 +1,57 @@
+package profiler
+
+import (
+	"math"
+	"runtime/metrics"
+	"time"
+)
+
+const gcPausesMetric = "/gc/pauses:seconds"
+
+// gcPauses reads the pauses of the garbage collector that happened since the previous read.
+// The runtime reports the pauses as a histogram, so their durations are estimated from the buckets.
+type gcPauses struct {
+	samples  []metrics.Sample
+	previous []uint64
+}
+
+func newGCPauses() *gcPauses {
+	pauses := &gcPauses{samples: []metrics.Sample{{Name: gcPausesMetric}}}
+	pauses.read()
+	return pauses
+}
+
+// read returns the number and the estimated total duration of the pauses since the previous read.
+func (pauses *gcPauses) read() (int, time.Duration) {
+	metrics.Read(pauses.samples)
+	if pauses.samples[0].Value.Kind() != metrics.KindFloat64Histogram {
+		return 0, 0
+	}
+	histogram := pauses.samples[0].Value.Float64Histogram()
+	count := 0
+	var seconds float64
+	for i, bucketCount := range histogram.Counts {
+		var delta uint64
+		if i < len(pauses.previous) {
+			delta = bucketCount - pauses.previous[i]
+		}
+		if delta == 0 {
+			continue
+		}
+		count += int(delta)
+		seconds += float64(delta) * bucketValue(histogram.Buckets[i], histogram.Buckets[i+1])
+	}
+	pauses.previous = append(pauses.previous[:0], histogram.Counts...)
+	return count, time.Duration(seconds * float64(time.Second))
+}
+
+// bucketValue returns the center of a bucket, or its finite boundary for the outer buckets.
+func bucketValue(low, high float64) float64 {
+	switch {
+	case math.IsInf(low, -1):
+		return high
+	case math.IsInf(high, 1):
+		return low
+	}
+	return (low + high) / 2
+}
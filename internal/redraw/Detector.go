@@ -0,0 +1,126 @@
+package redraw
+
+import (
+	"hash/maphash"
+	"math"
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/AllenDang/cimgui-go"
+)
+
+// Detector compares the draw data of each frame with the one of the last rendered frame.
+// The draw data covers the vertices, the indices, the commands with their textures and clip rectangles,
+// as well as the position, size, and scale of the display. Changes that are not part of the draw data,
+// such as new content of a texture, are reported with ForceRedraw.
+//
+// A Detector must only be used from the thread that runs the frame loop, except for ForceRedraw.
+type Detector struct {
+	enabled bool
+	forced  int32
+
+	seed    maphash.Seed
+	hash    maphash.Hash
+	last    uint64
+	valid   bool
+	skipped int
+}
+
+// NewDetector returns an enabled detector. The first frame is always considered changed.
+func NewDetector() *Detector {
+	return &Detector{
+		enabled: true,
+		seed:    maphash.MakeSeed(),
+	}
+}
+
+// Enabled returns true if unchanged frames are detected.
+func (detector *Detector) Enabled() bool {
+	return detector.enabled
+}
+
+// SetEnabled enables or disables the detection. While disabled, every frame is considered changed.
+func (detector *Detector) SetEnabled(enabled bool) {
+	detector.enabled = enabled
+	detector.valid = false
+}
+
+// ForceRedraw causes the next frame to be rendered, even if its draw data is unchanged.
+// It may be called from any goroutine.
+func (detector *Detector) ForceRedraw() {
+	atomic.StoreInt32(&detector.forced, 1)
+}
+
+// Skipped returns the number of frames that were found unchanged since the detector was created.
+func (detector *Detector) Skipped() int {
+	return detector.skipped
+}
+
+// Changed returns true if the given draw data differs from the one of the previous call, or if a redraw
+// was forced since then. The draw data of all viewports of a frame is passed together, in a stable order.
+// Draw data without a value, as for minimized viewports, is considered empty.
+// Frames with user callbacks are always considered changed, as the callbacks may draw anything.
+func (detector *Detector) Changed(drawData ...imgui.DrawData) bool {
+	forced := atomic.SwapInt32(&detector.forced, 0) != 0
+	if !detector.enabled {
+		return true
+	}
+	hash, callbacks := detector.sum(drawData)
+	changed := forced || callbacks || !detector.valid || (hash != detector.last)
+	detector.last = hash
+	detector.valid = true
+	if !changed {
+		detector.skipped++
+	}
+	return changed
+}
+
+func (detector *Detector) sum(drawData []imgui.DrawData) (hash uint64, callbacks bool) {
+	detector.hash.SetSeed(detector.seed)
+	for _, data := range drawData {
+		if data == 0 {
+			detector.writeUint64(0)
+			continue
+		}
+		detector.writeVec2(data.DisplayPos())
+		detector.writeVec2(data.DisplaySize())
+		detector.writeVec2(data.FramebufferScale())
+		lists := data.CommandLists()
+		detector.writeUint64(uint64(len(lists)))
+		for _, list := range lists {
+			detector.writeBuffer(list.GetVertexBuffer())
+			detector.writeBuffer(list.GetIndexBuffer())
+			commands := list.Commands()
+			detector.writeUint64(uint64(len(commands)))
+			for _, command := range commands {
+				clip := command.ClipRect()
+				detector.writeVec2(imgui.Vec2{X: clip.X, Y: clip.Y})
+				detector.writeVec2(imgui.Vec2{X: clip.Z, Y: clip.W})
+				detector.writeUint64(uint64(uintptr(command.TextureId())))
+				detector.writeUint64(uint64(command.VtxOffset())<<32 | uint64(command.IdxOffset()))
+				detector.writeUint64(uint64(command.ElemCount()))
+				callbacks = callbacks || command.HasUserCallback()
+			}
+		}
+	}
+	return detector.hash.Sum64(), callbacks
+}
+
+func (detector *Detector) writeBuffer(buffer unsafe.Pointer, size int) {
+	detector.writeUint64(uint64(size))
+	if size > 0 {
+		_, _ = detector.hash.Write(unsafe.Slice((*byte)(buffer), size))
+	}
+}
+
+func (detector *Detector) writeVec2(value imgui.Vec2) {
+	detector.writeUint64(uint64(math.Float32bits(value.X))<<32 | uint64(math.Float32bits(value.Y)))
+}
+
+func (detector *Detector) writeUint64(value uint64) {
+	var buf [8]byte
+	for i := range buf {
+		buf[i] = byte(value >> (i * 8))
+	}
+	_, _ = detector.hash.Write(buf[:])
+}
@@ -0,0 +1,178 @@
+package redraw_test
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/AllenDang/cimgui-go"
+	"github.com/ptxmac/cimgui-go-examples/internal/callbacks"
+	"github.com/ptxmac/cimgui-go-examples/internal/redraw"
+)
+
+// newContext creates an imgui context that builds frames without a platform or a renderer.
+func newContext(t *testing.T) {
+	t.Helper()
+	context := imgui.CreateContext()
+	t.Cleanup(context.Destroy)
+	io := imgui.CurrentIO()
+	io.SetDisplaySize(imgui.Vec2{X: 800, Y: 600})
+	io.Fonts().Build()
+}
+
+// frame builds a frame that draws two rectangles and an image, and returns its draw data.
+func frame(extra func(list imgui.DrawList)) imgui.DrawData {
+	imgui.NewFrame()
+	list := imgui.ForegroundDrawListNil()
+	list.AddRectFilled(imgui.Vec2{X: 10, Y: 10}, imgui.Vec2{X: 50, Y: 50}, 0xFF0000FF)
+	list.AddImage(fakeTextureID(42), imgui.Vec2{X: 60, Y: 10}, imgui.Vec2{X: 100, Y: 50})
+	list.AddRectFilled(imgui.Vec2{X: 110, Y: 10}, imgui.Vec2{X: 150, Y: 50}, 0xFF00FF00)
+	if extra != nil {
+		extra(list)
+	}
+	imgui.Render()
+	return imgui.CurrentDrawData()
+}
+
+// settled returns a detector that has seen the draw data of one frame already.
+func settled(t *testing.T) *redraw.Detector {
+	t.Helper()
+	detector := redraw.NewDetector()
+	if !detector.Changed(frame(nil)) {
+		t.Fatal("first frame is not considered changed")
+	}
+	return detector
+}
+
+func firstList(t *testing.T, drawData imgui.DrawData) imgui.DrawList {
+	t.Helper()
+	lists := drawData.CommandLists()
+	if len(lists) == 0 {
+		t.Fatal("draw data has no command lists")
+	}
+	return lists[len(lists)-1]
+}
+
+// fakeTextureID returns an identifier that holds the given value, like the identifiers of the renderers do.
+// The bits are copied instead of converted, as the pointer checks of the race detector reject converting small integers.
+func fakeTextureID(value uintptr) imgui.TextureID {
+	return *(*imgui.TextureID)(unsafe.Pointer(&value))
+}
+
+func flipByte(buffer unsafe.Pointer, size int, offset int) {
+	bytes := unsafe.Slice((*byte)(buffer), size)
+	bytes[offset] ^= 0xFF
+}
+
+func TestDetectorConsidersIdenticalFramesUnchanged(t *testing.T) {
+	newContext(t)
+	detector := settled(t)
+
+	for i := 0; i < 3; i++ {
+		if detector.Changed(frame(nil)) {
+			t.Fatalf("identical frame %d considered changed", i)
+		}
+	}
+	if detector.Skipped() != 3 {
+		t.Errorf("skipped %d frames, expected 3", detector.Skipped())
+	}
+}
+
+func TestDetectorConsidersChangedDrawDataChanged(t *testing.T) {
+	tt := []struct {
+		name   string
+		modify func(t *testing.T, drawData imgui.DrawData)
+	}{
+		{name: "vertex", modify: func(t *testing.T, drawData imgui.DrawData) {
+			buffer, size := firstList(t, drawData).GetVertexBuffer()
+			flipByte(buffer, size, size/2)
+		}},
+		{name: "index", modify: func(t *testing.T, drawData imgui.DrawData) {
+			buffer, size := firstList(t, drawData).GetIndexBuffer()
+			flipByte(buffer, size, 0)
+		}},
+		{name: "clip rect", modify: func(t *testing.T, drawData imgui.DrawData) {
+			command := firstList(t, drawData).Commands()[0]
+			clip := command.ClipRect()
+			clip.Z--
+			command.SetClipRect(clip)
+		}},
+		{name: "texture", modify: func(t *testing.T, drawData imgui.DrawData) {
+			command := firstList(t, drawData).Commands()[0]
+			command.SetTextureId(fakeTextureID(43))
+		}},
+		{name: "display size", modify: func(t *testing.T, drawData imgui.DrawData) {
+			drawData.SetDisplaySize(imgui.Vec2{X: 801, Y: 600})
+		}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			newContext(t)
+			detector := settled(t)
+
+			drawData := frame(nil)
+			tc.modify(t, drawData)
+			if !detector.Changed(drawData) {
+				t.Errorf("changed %s not detected", tc.name)
+			}
+			if !detector.Changed(frame(nil)) {
+				t.Errorf("change back of %s not detected", tc.name)
+			}
+		})
+	}
+}
+
+func TestDetectorConsidersAddedDrawingChanged(t *testing.T) {
+	newContext(t)
+	detector := settled(t)
+
+	drawData := frame(func(list imgui.DrawList) {
+		list.AddRectFilled(imgui.Vec2{X: 200, Y: 10}, imgui.Vec2{X: 250, Y: 50}, 0xFFFF0000)
+	})
+	if !detector.Changed(drawData) {
+		t.Error("added rectangle not detected")
+	}
+}
+
+func TestDetectorForceRedraw(t *testing.T) {
+	newContext(t)
+	detector := settled(t)
+
+	detector.ForceRedraw()
+	if !detector.Changed(frame(nil)) {
+		t.Error("forced redraw not rendered")
+	}
+	if detector.Changed(frame(nil)) {
+		t.Error("forced redraw applied to more than one frame")
+	}
+}
+
+func TestDetectorAlwaysRedrawsFramesWithCallbacks(t *testing.T) {
+	newContext(t)
+	detector := redraw.NewDetector()
+	withCallback := func(list imgui.DrawList) {
+		callbacks.Add(list, func(imgui.DrawList, imgui.DrawCmd, any) {}, nil)
+	}
+
+	for i := 0; i < 3; i++ {
+		if !detector.Changed(frame(withCallback)) {
+			t.Fatalf("frame %d with callback not rendered", i)
+		}
+	}
+}
+
+func TestDetectorConsidersEveryFrameChangedWhileDisabled(t *testing.T) {
+	newContext(t)
+	detector := settled(t)
+
+	detector.SetEnabled(false)
+	if !detector.Changed(frame(nil)) {
+		t.Error("frame of disabled detector not rendered")
+	}
+	detector.SetEnabled(true)
+	if !detector.Changed(frame(nil)) {
+		t.Error("first frame after enabling not rendered")
+	}
+	if detector.Changed(frame(nil)) {
+		t.Error("identical frame after enabling rendered")
+	}
+}
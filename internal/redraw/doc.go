@@ -0,0 +1,5 @@
+// Package redraw detects frames that would look exactly like the previous one.
+// Dashboards and other mostly static user interfaces produce identical draw data for many frames
+// in a row, so the frame loop can skip rendering and swapping the buffers for them. This saves
+// power on battery and bandwidth on remote desktop sessions.
+package redraw
@@ -0,0 +1,122 @@
+package redraw_test
+
+import (
+	"bytes"
+	"runtime"
+	"testing"
+
+	"github.com/AllenDang/cimgui-go"
+	"github.com/ptxmac/cimgui-go-examples/internal/redraw"
+	"github.com/ptxmac/cimgui-go-examples/internal/renderers"
+	"github.com/ptxmac/cimgui-go-examples/internal/renderers/headless"
+)
+
+const (
+	sceneWidth  = 320
+	sceneHeight = 240
+	imageSize   = 16
+)
+
+// scene is the content of a frame that is rendered for comparison.
+type scene struct {
+	rectX float32
+	color uint32
+	text  string
+	image imgui.TextureID
+}
+
+func (s scene) frame() imgui.DrawData {
+	imgui.NewFrame()
+	list := imgui.ForegroundDrawListNil()
+	list.AddRectFilled(imgui.Vec2{X: s.rectX, Y: 10}, imgui.Vec2{X: s.rectX + 40, Y: 50}, s.color)
+	list.AddTextVec2(imgui.Vec2{X: 10, Y: 60}, 0xFFFFFFFF, s.text)
+	list.AddImage(s.image, imgui.Vec2{X: 10, Y: 100}, imgui.Vec2{X: 10 + 4*imageSize, Y: 100 + 4*imageSize})
+	imgui.Render()
+	return imgui.CurrentDrawData()
+}
+
+// imagePixels returns an image of a single color.
+func imagePixels(red, green, blue uint8) []uint8 {
+	pixels := make([]uint8, imageSize*imageSize*4)
+	for i := 0; i < len(pixels); i += 4 {
+		pixels[i], pixels[i+1], pixels[i+2], pixels[i+3] = red, green, blue, 0xFF
+	}
+	return pixels
+}
+
+// TestDetectorSkipsOnlyFramesThatRenderIdentically renders every frame in software, including those the
+// detector considers unchanged, and compares the result with the one of the previous frame.
+func TestDetectorSkipsOnlyFramesThatRenderIdentically(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	context := imgui.CreateContext()
+	defer context.Destroy()
+	io := imgui.CurrentIO()
+	io.SetDisplaySize(imgui.Vec2{X: sceneWidth, Y: sceneHeight})
+	io.Fonts().Build()
+	glContext, err := headless.NewContext(sceneWidth, sceneHeight, 3, 2)
+	if err != nil {
+		t.Skipf("no OpenGL context for software rendering available: %v", err)
+	}
+	defer glContext.Destroy()
+	renderer, err := renderers.NewOpenGL3(io)
+	if err != nil {
+		t.Fatalf("failed to create renderer: %v", err)
+	}
+	defer renderer.Dispose()
+
+	detector := redraw.NewDetector()
+	tracked := detector.Textures(renderer)
+	image, err := tracked.CreateTexture(imagePixels(0xFF, 0, 0), imageSize, imageSize)
+	if err != nil {
+		t.Fatalf("failed to create texture: %v", err)
+	}
+	current := scene{rectX: 10, color: 0xFF00FF00, text: "Hello", image: image}
+
+	steps := []struct {
+		name    string
+		change  func()
+		skipped bool
+	}{
+		{name: "first frame"},
+		{name: "unchanged", skipped: true},
+		{name: "moved rectangle", change: func() { current.rectX += 20 }},
+		{name: "unchanged after move", skipped: true},
+		{name: "changed color", change: func() { current.color = 0xFFFF0000 }},
+		{name: "changed text", change: func() { current.text = "Hellp" }},
+		{name: "unchanged after text", skipped: true},
+		{name: "updated texture", change: func() {
+			if err := tracked.UpdateTexture(image, imagePixels(0, 0, 0xFF), imageSize, imageSize); err != nil {
+				t.Fatalf("failed to update texture: %v", err)
+			}
+		}},
+		{name: "unchanged after texture", skipped: true},
+	}
+	var last []uint8
+	for _, step := range steps {
+		if step.change != nil {
+			step.change()
+		}
+		renderer.NewFrame()
+		drawData := current.frame()
+		changed := detector.Changed(drawData)
+		renderer.PreRender([3]float32{0, 0, 0})
+		renderer.Render(drawData)
+		if err := renderer.Err(); err != nil {
+			t.Fatalf("%s: rendering failed: %v", step.name, err)
+		}
+		pixels := glContext.Pixels()
+
+		identical := bytes.Equal(pixels, last)
+		if !changed && !identical {
+			t.Errorf("%s: frame skipped, but it renders differently", step.name)
+		}
+		if changed == step.skipped {
+			t.Errorf("%s: frame considered changed: %v, expected %v", step.name, changed, !step.skipped)
+		}
+		if changed && identical {
+			t.Errorf("%s: frame renders like the previous one, the scene did not change", step.name)
+		}
+		last = pixels
+	}
+}
@@ -0,0 +1,32 @@
+package redraw
+
+import (
+	"github.com/AllenDang/cimgui-go"
+	"github.com/ptxmac/cimgui-go-examples/internal/textures"
+)
+
+// trackedTextures forces a redraw whenever a texture changes, as the draw data only refers to the identifiers.
+type trackedTextures struct {
+	textures.Renderer
+	detector *Detector
+}
+
+// Textures wraps the given texture renderer so that every created, updated, or deleted texture forces a redraw.
+func (detector *Detector) Textures(renderer textures.Renderer) textures.Renderer {
+	return trackedTextures{Renderer: renderer, detector: detector}
+}
+
+func (tracked trackedTextures) CreateTexture(pixels []uint8, width, height int) (imgui.TextureID, error) {
+	tracked.detector.ForceRedraw()
+	return tracked.Renderer.CreateTexture(pixels, width, height)
+}
+
+func (tracked trackedTextures) UpdateTexture(id imgui.TextureID, pixels []uint8, width, height int) error {
+	tracked.detector.ForceRedraw()
+	return tracked.Renderer.UpdateTexture(id, pixels, width, height)
+}
+
+func (tracked trackedTextures) DeleteTexture(id imgui.TextureID) {
+	tracked.detector.ForceRedraw()
+	tracked.Renderer.DeleteTexture(id)
+}
@@ -0,0 +1,58 @@
+package redraw_test
+
+import (
+	"testing"
+
+	"github.com/AllenDang/cimgui-go"
+	"github.com/ptxmac/cimgui-go-examples/internal/textures"
+)
+
+type nullRenderer struct {
+	calls int
+}
+
+func (renderer *nullRenderer) CreateTexture(pixels []uint8, width, height int) (imgui.TextureID, error) {
+	renderer.calls++
+	return fakeTextureID(1), nil
+}
+
+func (renderer *nullRenderer) UpdateTexture(id imgui.TextureID, pixels []uint8, width, height int) error {
+	renderer.calls++
+	return nil
+}
+
+func (renderer *nullRenderer) DeleteTexture(id imgui.TextureID) {
+	renderer.calls++
+}
+
+func TestTexturesForceRedraw(t *testing.T) {
+	tt := []struct {
+		name   string
+		change func(renderer textures.Renderer)
+	}{
+		{name: "create", change: func(renderer textures.Renderer) {
+			_, _ = renderer.CreateTexture(make([]uint8, 4), 1, 1)
+		}},
+		{name: "update", change: func(renderer textures.Renderer) {
+			_ = renderer.UpdateTexture(fakeTextureID(1), make([]uint8, 4), 1, 1)
+		}},
+		{name: "delete", change: func(renderer textures.Renderer) {
+			renderer.DeleteTexture(fakeTextureID(1))
+		}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			newContext(t)
+			detector := settled(t)
+			renderer := &nullRenderer{}
+
+			tc.change(detector.Textures(renderer))
+			if renderer.calls != 1 {
+				t.Errorf("renderer called %d times, expected once", renderer.calls)
+			}
+			if !detector.Changed(frame(nil)) {
+				t.Errorf("%s of texture did not force a redraw", tc.name)
+			}
+		})
+	}
+}
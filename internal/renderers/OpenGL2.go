@@ -2,17 +2,35 @@ package renderers
 
 import (
 	"fmt"
-	"unsafe"
+	"strings"
+	"time"
 
 	"github.com/AllenDang/cimgui-go"
+	"github.com/ptxmac/cimgui-go-examples/internal/callbacks"
 	"github.com/ptxmac/cimgui-go-examples/internal/renderers/gl/v2.1/gl"
 )
 
+// vertexBufferObjectExtension provides buffer objects for vertex and index data, in place of client-side arrays.
+const vertexBufferObjectExtension = "GL_ARB_vertex_buffer_object"
+
 // OpenGL2 implements a renderer based on github.com/go-gl/gl (v2.1).
 type OpenGL2 struct {
 	imguiIO imgui.IO
 
 	fontTexture uint32
+	textures    map[uint32]struct{}
+
+	// vboHandle and elementsHandle are only created if the driver supports vertex buffer objects.
+	// Without them, the draw data is passed as client-side vertex arrays.
+	vboHandle      uint32
+	elementsHandle uint32
+
+	timer gpuTimer
+	// frame collects the stats of the current frame, stats holds those of the previous one.
+	frame Stats
+	stats Stats
+
+	err error
 }
 
 // NewOpenGL2 attempts to initialize a renderer.
@@ -24,15 +42,55 @@ func NewOpenGL2(io imgui.IO) (*OpenGL2, error) {
 	}
 
 	renderer := &OpenGL2{
-		imguiIO: io,
+		imguiIO:  io,
+		textures: make(map[uint32]struct{}),
 	}
+	extensions := strings.Fields(gl.GoStr(gl.GetString(gl.EXTENSIONS)))
 	renderer.createFontsTexture()
+	renderer.createBuffers(extensions)
+	renderer.createTimer(extensions)
+
+	io.SetBackendFlags(io.BackendFlags() | imgui.BackendFlagsRendererHasVtxOffset | imgui.BackendFlagsRendererHasViewports)
+
 	return renderer, nil
 }
 
 // Dispose cleans up the resources.
 func (renderer *OpenGL2) Dispose() {
+	for handle := range renderer.textures {
+		renderer.DeleteTexture(textureID(handle))
+	}
 	renderer.destroyFontsTexture()
+	renderer.deleteBuffers()
+	renderer.deleteTimer()
+}
+
+// NewFrame prepares the renderer for a new frame.
+// It only builds and uploads the font atlas if the atlas is not built, for example after fonts were added to it.
+// An atlas that was built elsewhere, such as by fonts.Manager.Apply, is not noticed; RebuildFonts uploads it.
+func (renderer *OpenGL2) NewFrame() {
+	renderer.stats = renderer.frame
+	renderer.stats.GPUTime = renderer.timer.poll()
+	renderer.stats.GPUTimeSupported = renderer.timer.supported
+	renderer.frame = Stats{}
+	if !renderer.imguiIO.Fonts().TexReady() {
+		renderer.RebuildFonts()
+	}
+}
+
+// RebuildFonts builds the font atlas and replaces the font texture with the result.
+// It must not be called between imgui.NewFrame() and imgui.Render().
+func (renderer *OpenGL2) RebuildFonts() {
+	renderer.destroyFontsTexture()
+	renderer.createFontsTexture()
+}
+
+// Err returns a failure of the graphics system that prevents further rendering, such as a lost context, or nil.
+// It is meant to be called once per frame, with the context of the main window being current.
+// Other errors of OpenGL are reported in the stats of the frame.
+func (renderer *OpenGL2) Err() error {
+	collectErrors(gl.GetError, &renderer.err, &renderer.frame)
+	return renderer.err
 }
 
 // PreRender clears the framebuffer.
@@ -41,20 +99,32 @@ func (renderer *OpenGL2) PreRender(clearColor [3]float32) {
 	gl.Clear(gl.COLOR_BUFFER_BIT)
 }
 
-// Render translates the ImGui draw data to OpenGL3 commands.
-func (renderer *OpenGL2) Render(displaySize [2]float32, framebufferSize [2]float32, drawData imgui.DrawData) {
+// Stats returns the measurements of the previous frame.
+func (renderer *OpenGL2) Stats() Stats {
+	return renderer.stats
+}
+
+// Render translates the ImGui draw data of the main viewport to OpenGL2 commands.
+// The time the GPU takes for these commands is measured, if the driver supports timer queries.
+func (renderer *OpenGL2) Render(drawData imgui.DrawData) {
+	renderer.timer.begin()
+	renderer.render(drawData)
+	renderer.timer.end()
+}
+
+// render translates the ImGui draw data to OpenGL2 commands.
+// The projection and the clip rectangles are derived from the display position, display size and
+// framebuffer scale of the draw data, which allows to render viewports other than the main one.
+func (renderer *OpenGL2) render(drawData imgui.DrawData) {
 	// Avoid rendering when minimized, scale coordinates for retina displays (screen coordinates != framebuffer coordinates)
-	displayWidth, displayHeight := displaySize[0], displaySize[1]
-	fbWidth, fbHeight := framebufferSize[0], framebufferSize[1]
+	fbWidth, fbHeight := framebufferSize(drawData)
 	if (fbWidth <= 0) || (fbHeight <= 0) {
 		return
 	}
-	drawData.ScaleClipRects(imgui.Vec2{
-		X: fbWidth / displayWidth,
-		Y: fbHeight / displayHeight,
-	})
+	clipOffset := drawData.DisplayPos()      // (0,0) unless using multi-viewports
+	clipScale := drawData.FramebufferScale() // (1,1) unless using retina display which are often (2,2)
 
-	// Setup render state: alpha-blending enabled, no face culling, no depth testing, scissor enabled, vertex/texcoord/color pointers, polygon fill.
+	// Backup GL state
 	var lastTexture int32
 	gl.GetIntegerv(gl.TEXTURE_BINDING_2D, &lastTexture)
 	var lastPolygonMode [2]int32
@@ -63,36 +133,20 @@ func (renderer *OpenGL2) Render(displaySize [2]float32, framebufferSize [2]float
 	gl.GetIntegerv(gl.VIEWPORT, &lastViewport[0])
 	var lastScissorBox [4]int32
 	gl.GetIntegerv(gl.SCISSOR_BOX, &lastScissorBox[0])
+	var lastArrayBuffer, lastElementArrayBuffer int32
+	if renderer.vboHandle != 0 {
+		gl.GetIntegerv(gl.ARRAY_BUFFER_BINDING, &lastArrayBuffer)
+		gl.GetIntegerv(gl.ELEMENT_ARRAY_BUFFER_BINDING, &lastElementArrayBuffer)
+	}
+	var lastProgram int32
+	gl.GetIntegerv(gl.CURRENT_PROGRAM, &lastProgram)
 	gl.PushAttrib(gl.ENABLE_BIT | gl.COLOR_BUFFER_BIT | gl.TRANSFORM_BIT)
-	gl.Enable(gl.BLEND)
-	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
-	gl.Disable(gl.CULL_FACE)
-	gl.Disable(gl.DEPTH_TEST)
-	gl.Disable(gl.LIGHTING)
-	gl.Disable(gl.COLOR_MATERIAL)
-	gl.Enable(gl.SCISSOR_TEST)
-	gl.EnableClientState(gl.VERTEX_ARRAY)
-	gl.EnableClientState(gl.TEXTURE_COORD_ARRAY)
-	gl.EnableClientState(gl.COLOR_ARRAY)
-	gl.Enable(gl.TEXTURE_2D)
-	gl.PolygonMode(gl.FRONT_AND_BACK, gl.FILL)
-
-	// You may want this if using this code in an OpenGL 3+ context where shaders may be bound
-	// gl.UseProgram(0)
-
-	// Setup viewport, orthographic projection matrix
-	// Our visible cimgui space lies from draw_data->DisplayPos (top left) to draw_data->DisplayPos+data_data->DisplaySize (bottom right).
-	// DisplayMin is typically (0,0) for single viewport apps.
-	gl.Viewport(0, 0, int32(fbWidth), int32(fbHeight))
 	gl.MatrixMode(gl.PROJECTION)
 	gl.PushMatrix()
-	gl.LoadIdentity()
-	gl.Ortho(0, float64(displayWidth), float64(displayHeight), 0, -1, 1)
 	gl.MatrixMode(gl.MODELVIEW)
 	gl.PushMatrix()
-	gl.LoadIdentity()
+	renderer.setupRenderState(drawData, fbWidth, fbHeight)
 
-	vertexSize, vertexOffsetPos, vertexOffsetUv, vertexOffsetCol := imgui.VertexBufferLayout()
 	indexSize := imgui.IndexBufferLayout()
 
 	drawType := gl.UNSIGNED_SHORT
@@ -103,22 +157,45 @@ func (renderer *OpenGL2) Render(displaySize [2]float32, framebufferSize [2]float
 
 	// Render command lists
 	for _, commandList := range drawData.CommandLists() {
-		vertexBuffer, _ := commandList.GetVertexBuffer()
-		indexBuffer, _ := commandList.GetIndexBuffer()
-		indexBufferOffset := uintptr(indexBuffer)
+		vertexBuffer, vertexBufferSize := commandList.GetVertexBuffer()
+		indexBuffer, indexBufferSize := commandList.GetIndexBuffer()
+		renderer.frame.UploadedBytes += vertexBufferSize + indexBufferSize // Either buffered, or read from the client arrays by the driver
+		var indexBufferOffset uintptr
+		vertexBase := uintptr(vertexBuffer)
 
-		gl.VertexPointer(2, gl.FLOAT, int32(vertexSize), unsafe.Pointer(uintptr(vertexBuffer)+uintptr(vertexOffsetPos)))
-		gl.TexCoordPointer(2, gl.FLOAT, int32(vertexSize), unsafe.Pointer(uintptr(vertexBuffer)+uintptr(vertexOffsetUv)))
-		gl.ColorPointer(4, gl.UNSIGNED_BYTE, int32(vertexSize), unsafe.Pointer(uintptr(vertexBuffer)+uintptr(vertexOffsetCol)))
+		if renderer.vboHandle != 0 {
+			gl.BindBuffer(gl.ARRAY_BUFFER, renderer.vboHandle)
+			gl.BufferData(gl.ARRAY_BUFFER, vertexBufferSize, vertexBuffer, gl.STREAM_DRAW)
+			gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, renderer.elementsHandle)
+			gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, indexBufferSize, indexBuffer, gl.STREAM_DRAW)
+			vertexBase = 0 // The pointers are offsets into the bound buffers
+		} else {
+			indexBufferOffset = uintptr(indexBuffer)
+		}
+		vertexOffset := -1
 
 		for _, command := range commandList.Commands() {
 			if command.HasUserCallback() {
-				command.CallUserCallback(commandList)
-			} else {
-				clipRect := command.ClipRect()
-				gl.Scissor(int32(clipRect.X), int32(fbHeight)-int32(clipRect.W), int32(clipRect.Z-clipRect.X), int32(clipRect.W-clipRect.Y))
-				gl.BindTexture(gl.TEXTURE_2D, uint32(uintptr(command.TextureId())))
+				// The sentinel of callbacks.AddResetRenderState only asks for the setup, which follows every callback.
+				box, visible := scissorBox(command.ClipRect(), clipOffset, clipScale, fbWidth, fbHeight)
+				if !callbacks.IsResetRenderState(command) {
+					if visible {
+						gl.Scissor(box[0], box[1], box[2], box[3])
+					}
+					command.CallUserCallback(commandList)
+				}
+				renderer.setupRenderState(drawData, fbWidth, fbHeight)
+				vertexOffset = -1 // The vertex pointers are set again by the next command
+			} else if box, visible := scissorBox(command.ClipRect(), clipOffset, clipScale, fbWidth, fbHeight); visible {
+				// Large meshes with 16-bit indices are split into parts, each indexing from its own first vertex.
+				if int(command.VtxOffset()) != vertexOffset {
+					vertexOffset = int(command.VtxOffset())
+					setVertexPointers(vertexBase, vertexOffset)
+				}
+				gl.Scissor(box[0], box[1], box[2], box[3])
+				gl.BindTexture(gl.TEXTURE_2D, textureHandle(command.TextureId()))
 				gl.DrawElementsWithOffset(gl.TRIANGLES, int32(command.ElemCount()), uint32(drawType), indexBufferOffset)
+				renderer.frame.DrawCalls++
 			}
 
 			indexBufferOffset += uintptr(command.ElemCount() * uint32(indexSize))
@@ -129,6 +206,11 @@ func (renderer *OpenGL2) Render(displaySize [2]float32, framebufferSize [2]float
 	gl.DisableClientState(gl.COLOR_ARRAY)
 	gl.DisableClientState(gl.TEXTURE_COORD_ARRAY)
 	gl.DisableClientState(gl.VERTEX_ARRAY)
+	if renderer.vboHandle != 0 {
+		gl.BindBuffer(gl.ARRAY_BUFFER, uint32(lastArrayBuffer))
+		gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, uint32(lastElementArrayBuffer))
+	}
+	gl.UseProgram(uint32(lastProgram))
 	gl.BindTexture(gl.TEXTURE_2D, uint32(lastTexture))
 	gl.MatrixMode(gl.MODELVIEW)
 	gl.PopMatrix()
@@ -141,6 +223,105 @@ func (renderer *OpenGL2) Render(displaySize [2]float32, framebufferSize [2]float
 	gl.Scissor(lastScissorBox[0], lastScissorBox[1], lastScissorBox[2], lastScissorBox[3])
 }
 
+// setupRenderState sets the state for rendering the draw data: alpha-blending enabled, no face culling,
+// no depth testing, scissor enabled, vertex/texcoord/color arrays, polygon fill, as well as the projection.
+// It is applied before the first command, and again after user callbacks, which may change any state.
+// The vertex pointers are set per command, as they depend on the vertex offset of the command.
+func (renderer *OpenGL2) setupRenderState(drawData imgui.DrawData, fbWidth, fbHeight float32) {
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.Disable(gl.CULL_FACE)
+	gl.Disable(gl.DEPTH_TEST)
+	gl.Disable(gl.LIGHTING)
+	gl.Disable(gl.COLOR_MATERIAL)
+	gl.Enable(gl.SCISSOR_TEST)
+	gl.EnableClientState(gl.VERTEX_ARRAY)
+	gl.EnableClientState(gl.TEXTURE_COORD_ARRAY)
+	gl.EnableClientState(gl.COLOR_ARRAY)
+	gl.Enable(gl.TEXTURE_2D)
+	gl.PolygonMode(gl.FRONT_AND_BACK, gl.FILL)
+	gl.UseProgram(0) // Callbacks may have left a shader bound, which would replace the fixed pipeline
+
+	// Setup viewport, orthographic projection matrix
+	// Our visible cimgui space lies from draw_data->DisplayPos (top left) to draw_data->DisplayPos+data_data->DisplaySize (bottom right).
+	// DisplayMin is typically (0,0) for single viewport apps.
+	gl.Viewport(0, 0, int32(fbWidth), int32(fbHeight))
+	gl.MatrixMode(gl.PROJECTION)
+	gl.LoadIdentity()
+	displayPos, displaySize := drawData.DisplayPos(), drawData.DisplaySize()
+	gl.Ortho(float64(displayPos.X), float64(displayPos.X+displaySize.X), float64(displayPos.Y+displaySize.Y), float64(displayPos.Y), -1, 1)
+	gl.MatrixMode(gl.MODELVIEW)
+	gl.LoadIdentity()
+
+	if renderer.vboHandle != 0 {
+		gl.BindBuffer(gl.ARRAY_BUFFER, renderer.vboHandle)
+		gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, renderer.elementsHandle)
+	}
+}
+
+// RenderWindow renders the draw data of a secondary viewport.
+// The OpenGL context of the viewport window must be current.
+func (renderer *OpenGL2) RenderWindow(viewport imgui.Viewport) {
+	if (viewport.Flags() & imgui.ViewportFlagsNoRendererClear) == 0 {
+		renderer.PreRender([3]float32{0, 0, 0})
+	}
+	renderer.render(viewport.DrawData())
+}
+
+// CreateTexture uploads tightly packed 8-bit RGBA pixels to the graphics system.
+// The returned identifier can be used with imgui.Image and similar functions.
+func (renderer *OpenGL2) CreateTexture(pixels []uint8, width, height int) (imgui.TextureID, error) {
+	err := validateTextureData(pixels, width, height)
+	if err != nil {
+		return nil, err
+	}
+
+	var handle uint32
+	gl.GenTextures(1, &handle)
+	renderer.textures[handle] = struct{}{}
+	renderer.uploadTexture(handle, pixels, width, height)
+	return textureID(handle), nil
+}
+
+// UpdateTexture replaces the content of a texture previously created with CreateTexture.
+// The dimensions may differ from the previous content.
+func (renderer *OpenGL2) UpdateTexture(id imgui.TextureID, pixels []uint8, width, height int) error {
+	handle := textureHandle(id)
+	if _, known := renderer.textures[handle]; !known {
+		return ErrUnknownTexture
+	}
+	err := validateTextureData(pixels, width, height)
+	if err != nil {
+		return err
+	}
+
+	renderer.uploadTexture(handle, pixels, width, height)
+	return nil
+}
+
+// DeleteTexture releases a texture previously created with CreateTexture.
+func (renderer *OpenGL2) DeleteTexture(id imgui.TextureID) {
+	handle := textureHandle(id)
+	if _, known := renderer.textures[handle]; !known {
+		return
+	}
+	gl.DeleteTextures(1, &handle)
+	delete(renderer.textures, handle)
+}
+
+func (renderer *OpenGL2) uploadTexture(handle uint32, pixels []uint8, width, height int) {
+	var lastTexture int32
+	gl.GetIntegerv(gl.TEXTURE_BINDING_2D, &lastTexture)
+	gl.BindTexture(gl.TEXTURE_2D, handle)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	gl.PixelStorei(gl.UNPACK_ROW_LENGTH, 0)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, int32(width), int32(height),
+		0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(pixels))
+	gl.BindTexture(gl.TEXTURE_2D, uint32(lastTexture))
+	renderer.frame.UploadedBytes += width * height * bytesPerRGBAPixel
+}
+
 func (renderer *OpenGL2) createFontsTexture() {
 	// Build texture atlas
 	pixels, width, height, _ := renderer.imguiIO.Fonts().GetTextureDataAsRGBA32()
@@ -155,10 +336,11 @@ func (renderer *OpenGL2) createFontsTexture() {
 	gl.PixelStorei(gl.UNPACK_ROW_LENGTH, 0)
 	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, int32(width), int32(height),
 		0, gl.RGBA, gl.UNSIGNED_BYTE, pixels)
+	renderer.frame.UploadedBytes += int(width * height * bytesPerRGBAPixel)
 
 	// Store our identifier
 
-	renderer.imguiIO.Fonts().SetTexID(imgui.TextureID(uintptr(renderer.fontTexture)))
+	renderer.imguiIO.Fonts().SetTexID(textureID(renderer.fontTexture))
 
 	// Restore state
 	gl.BindTexture(gl.TEXTURE_2D, uint32(lastTexture))
@@ -171,3 +353,55 @@ func (renderer *OpenGL2) destroyFontsTexture() {
 		renderer.fontTexture = 0
 	}
 }
+
+// createBuffers creates the vertex buffer objects, if the driver supports GL_ARB_vertex_buffer_object.
+// They avoid copying the vertices for each draw call, which is slow on remote X displays.
+func (renderer *OpenGL2) createBuffers(extensions []string) {
+	if !hasExtension(extensions, vertexBufferObjectExtension) {
+		return
+	}
+	gl.GenBuffers(1, &renderer.vboHandle)
+	gl.GenBuffers(1, &renderer.elementsHandle)
+}
+
+func (renderer *OpenGL2) deleteBuffers() {
+	if renderer.vboHandle != 0 {
+		gl.DeleteBuffers(1, &renderer.vboHandle)
+	}
+	renderer.vboHandle = 0
+	if renderer.elementsHandle != 0 {
+		gl.DeleteBuffers(1, &renderer.elementsHandle)
+	}
+	renderer.elementsHandle = 0
+}
+
+// createTimer prepares measuring the GPU time, if the driver supports GL_ARB_timer_query.
+func (renderer *OpenGL2) createTimer(extensions []string) {
+	if !hasExtension(extensions, timerQueryExtension) {
+		return
+	}
+
+	var queries [timerRingSize]uint32
+	gl.GenQueries(timerRingSize, &queries[0])
+	renderer.timer = newGPUTimer(queries, timerFuncs{
+		begin: func(query uint32) { gl.BeginQuery(gl.TIME_ELAPSED, query) },
+		end:   func() { gl.EndQuery(gl.TIME_ELAPSED) },
+		available: func(query uint32) bool {
+			var available int32
+			gl.GetQueryObjectiv(query, gl.QUERY_RESULT_AVAILABLE, &available)
+			return available != 0
+		},
+		result: func(query uint32) time.Duration {
+			var nanoseconds uint64
+			gl.GetQueryObjectui64v(query, gl.QUERY_RESULT, &nanoseconds)
+			return time.Duration(nanoseconds)
+		},
+	})
+}
+
+func (renderer *OpenGL2) deleteTimer() {
+	if renderer.timer.supported {
+		gl.DeleteQueries(timerRingSize, &renderer.timer.queries[0])
+		renderer.timer = gpuTimer{}
+	}
+}
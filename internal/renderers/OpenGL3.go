@@ -3,8 +3,10 @@ package renderers
 import (
 	_ "embed" // using embed for the shader sources
 	"fmt"
+	"time"
 
 	"github.com/AllenDang/cimgui-go"
+	"github.com/ptxmac/cimgui-go-examples/internal/callbacks"
 	"github.com/ptxmac/cimgui-go-examples/internal/renderers/gl/v3.2-core/gl"
 )
 
@@ -30,6 +32,14 @@ type OpenGL3 struct {
 	attribLocationColor    int32
 	vboHandle              uint32
 	elementsHandle         uint32
+	textures               map[uint32]struct{}
+
+	timer gpuTimer
+	// frame collects the stats of the current frame, stats holds those of the previous one.
+	frame Stats
+	stats Stats
+
+	err error
 }
 
 // NewOpenGL3 attempts to initialize a renderer.
@@ -43,17 +53,51 @@ func NewOpenGL3(io imgui.IO) (*OpenGL3, error) {
 	renderer := &OpenGL3{
 		imguiIO:     io,
 		glslVersion: "#version 150",
+		textures:    make(map[uint32]struct{}),
 	}
 	renderer.createDeviceObjects()
+	renderer.createTimer()
 
-	io.SetBackendFlags(io.BackendFlags() | imgui.BackendFlagsRendererHasVtxOffset)
+	io.SetBackendFlags(io.BackendFlags() | imgui.BackendFlagsRendererHasVtxOffset | imgui.BackendFlagsRendererHasViewports)
 
 	return renderer, nil
 }
 
 // Dispose cleans up the resources.
 func (renderer *OpenGL3) Dispose() {
+	for handle := range renderer.textures {
+		renderer.DeleteTexture(textureID(handle))
+	}
 	renderer.invalidateDeviceObjects()
+	renderer.deleteTimer()
+}
+
+// NewFrame prepares the renderer for a new frame.
+// It only builds and uploads the font atlas if the atlas is not built, for example after fonts were added to it.
+// An atlas that was built elsewhere, such as by fonts.Manager.Apply, is not noticed; RebuildFonts uploads it.
+func (renderer *OpenGL3) NewFrame() {
+	renderer.stats = renderer.frame
+	renderer.stats.GPUTime = renderer.timer.poll()
+	renderer.stats.GPUTimeSupported = renderer.timer.supported
+	renderer.frame = Stats{}
+	if !renderer.imguiIO.Fonts().TexReady() {
+		renderer.RebuildFonts()
+	}
+}
+
+// RebuildFonts builds the font atlas and replaces the font texture with the result.
+// It must not be called between imgui.NewFrame() and imgui.Render().
+func (renderer *OpenGL3) RebuildFonts() {
+	renderer.destroyFontsTexture()
+	renderer.createFontsTexture()
+}
+
+// Err returns a failure of the graphics system that prevents further rendering, such as a lost context, or nil.
+// It is meant to be called once per frame, with the context of the main window being current.
+// Other errors of OpenGL are reported in the stats of the frame.
+func (renderer *OpenGL3) Err() error {
+	collectErrors(gl.GetError, &renderer.err, &renderer.frame)
+	return renderer.err
 }
 
 // PreRender clears the framebuffer.
@@ -62,18 +106,30 @@ func (renderer *OpenGL3) PreRender(clearColor [3]float32) {
 	gl.Clear(gl.COLOR_BUFFER_BIT)
 }
 
-// Render translates the ImGui draw data to OpenGL3 commands.
-func (renderer *OpenGL3) Render(displaySize [2]float32, framebufferSize [2]float32, drawData imgui.DrawData) {
+// Stats returns the measurements of the previous frame.
+func (renderer *OpenGL3) Stats() Stats {
+	return renderer.stats
+}
+
+// Render translates the ImGui draw data of the main viewport to OpenGL3 commands.
+// The time the GPU takes for these commands is measured, if the driver supports timer queries.
+func (renderer *OpenGL3) Render(drawData imgui.DrawData) {
+	renderer.timer.begin()
+	renderer.render(drawData)
+	renderer.timer.end()
+}
+
+// render translates the ImGui draw data to OpenGL3 commands.
+// The projection and the clip rectangles are derived from the display position, display size and
+// framebuffer scale of the draw data, which allows to render viewports other than the main one.
+func (renderer *OpenGL3) render(drawData imgui.DrawData) {
 	// Avoid rendering when minimized, scale coordinates for retina displays (screen coordinates != framebuffer coordinates)
-	displayWidth, displayHeight := displaySize[0], displaySize[1]
-	fbWidth, fbHeight := framebufferSize[0], framebufferSize[1]
+	fbWidth, fbHeight := framebufferSize(drawData)
 	if (fbWidth <= 0) || (fbHeight <= 0) {
 		return
 	}
-	drawData.ScaleClipRects(imgui.Vec2{
-		X: fbWidth / displayWidth,
-		Y: fbHeight / displayHeight,
-	})
+	clipOffset := drawData.DisplayPos()      // (0,0) unless using multi-viewports
+	clipScale := drawData.FramebufferScale() // (1,1) unless using retina display which are often (2,2)
 
 	// Backup GL state
 	var lastActiveTexture int32
@@ -114,44 +170,13 @@ func (renderer *OpenGL3) Render(displaySize [2]float32, framebufferSize [2]float
 	lastEnableDepthTest := gl.IsEnabled(gl.DEPTH_TEST)
 	lastEnableScissorTest := gl.IsEnabled(gl.SCISSOR_TEST)
 
-	// Setup render state: alpha-blending enabled, no face culling, no depth testing, scissor enabled, polygon fill
-	gl.Enable(gl.BLEND)
-	gl.BlendEquation(gl.FUNC_ADD)
-	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
-	gl.Disable(gl.CULL_FACE)
-	gl.Disable(gl.DEPTH_TEST)
-	gl.Enable(gl.SCISSOR_TEST)
-	gl.PolygonMode(gl.FRONT_AND_BACK, gl.FILL)
-
-	// Setup viewport, orthographic projection matrix
-	// Our visible cimgui space lies from draw_data->DisplayPos (top left) to draw_data->DisplayPos+data_data->DisplaySize (bottom right).
-	// DisplayMin is typically (0,0) for single viewport apps.
-	gl.Viewport(0, 0, int32(fbWidth), int32(fbHeight))
-	orthoProjection := [4][4]float32{
-		{2.0 / displayWidth, 0.0, 0.0, 0.0},
-		{0.0, 2.0 / -displayHeight, 0.0, 0.0},
-		{0.0, 0.0, -1.0, 0.0},
-		{-1.0, 1.0, 0.0, 1.0},
-	}
-	gl.UseProgram(renderer.shaderHandle)
-	gl.Uniform1i(renderer.attribLocationTex, 0)
-	gl.UniformMatrix4fv(renderer.attribLocationProjMtx, 1, false, &orthoProjection[0][0])
-	gl.BindSampler(0, 0) // Rely on combined texture/sampler state.
-
 	// Recreate the VAO every time
 	// (This is to easily allow multiple GL contexts. VAO are not shared among GL contexts, and
 	// we don't track creation/deletion of windows so we don't have an obvious key to use to cache them.)
 	var vaoHandle uint32
 	gl.GenVertexArrays(1, &vaoHandle)
-	gl.BindVertexArray(vaoHandle)
-	gl.BindBuffer(gl.ARRAY_BUFFER, renderer.vboHandle)
-	gl.EnableVertexAttribArray(uint32(renderer.attribLocationPosition))
-	gl.EnableVertexAttribArray(uint32(renderer.attribLocationUV))
-	gl.EnableVertexAttribArray(uint32(renderer.attribLocationColor))
-	vertexSize, vertexOffsetPos, vertexOffsetUv, vertexOffsetCol := imgui.VertexBufferLayout()
-	gl.VertexAttribPointerWithOffset(uint32(renderer.attribLocationPosition), 2, gl.FLOAT, false, int32(vertexSize), uintptr(vertexOffsetPos))
-	gl.VertexAttribPointerWithOffset(uint32(renderer.attribLocationUV), 2, gl.FLOAT, false, int32(vertexSize), uintptr(vertexOffsetUv))
-	gl.VertexAttribPointerWithOffset(uint32(renderer.attribLocationColor), 4, gl.UNSIGNED_BYTE, true, int32(vertexSize), uintptr(vertexOffsetCol))
+	renderer.setupRenderState(drawData, fbWidth, fbHeight, vaoHandle)
+
 	indexSize := imgui.IndexBufferLayout()
 	drawType := gl.UNSIGNED_SHORT
 	const bytesPerUint32 = 4
@@ -168,16 +193,25 @@ func (renderer *OpenGL3) Render(displaySize [2]float32, framebufferSize [2]float
 		indexBuffer, indexBufferSize := list.GetIndexBuffer()
 		gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, renderer.elementsHandle)
 		gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, indexBufferSize, indexBuffer, gl.STREAM_DRAW)
+		renderer.frame.UploadedBytes += vertexBufferSize + indexBufferSize
 
 		for _, cmd := range list.Commands() {
 			if cmd.HasUserCallback() {
-				cmd.CallUserCallback(list)
-			} else {
-				gl.BindTexture(gl.TEXTURE_2D, uint32(uintptr(cmd.TextureId())))
-				clipRect := cmd.ClipRect()
-				gl.Scissor(int32(clipRect.X), int32(fbHeight)-int32(clipRect.W), int32(clipRect.Z-clipRect.X), int32(clipRect.W-clipRect.Y))
+				// The sentinel of callbacks.AddResetRenderState only asks for the setup, which follows every callback.
+				box, visible := scissorBox(cmd.ClipRect(), clipOffset, clipScale, fbWidth, fbHeight)
+				if !callbacks.IsResetRenderState(cmd) {
+					if visible {
+						gl.Scissor(box[0], box[1], box[2], box[3])
+					}
+					cmd.CallUserCallback(list)
+				}
+				renderer.setupRenderState(drawData, fbWidth, fbHeight, vaoHandle)
+			} else if box, visible := scissorBox(cmd.ClipRect(), clipOffset, clipScale, fbWidth, fbHeight); visible {
+				gl.Scissor(box[0], box[1], box[2], box[3])
+				gl.BindTexture(gl.TEXTURE_2D, textureHandle(cmd.TextureId()))
 				gl.DrawElementsBaseVertexWithOffset(gl.TRIANGLES, int32(cmd.ElemCount()), uint32(drawType),
 					uintptr(cmd.IdxOffset()*uint32(indexSize)), int32(cmd.VtxOffset()))
+				renderer.frame.DrawCalls++
 			}
 		}
 	}
@@ -269,7 +303,7 @@ func (renderer *OpenGL3) createDeviceObjects() {
 func (renderer *OpenGL3) createFontsTexture() {
 	// Build texture atlas
 	io := imgui.CurrentIO()
-	pixels, width, height, _ := io.Fonts().GetTextureDataAsAlpha8()
+	pixels, width, height, _ := io.Fonts().GetTextureDataAsRGBA32()
 
 	// Upload texture to graphics system
 	var lastTexture int32
@@ -279,16 +313,123 @@ func (renderer *OpenGL3) createFontsTexture() {
 	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
 	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
 	gl.PixelStorei(gl.UNPACK_ROW_LENGTH, 0)
-	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RED, int32(width), int32(height),
-		0, gl.RED, gl.UNSIGNED_BYTE, pixels)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, int32(width), int32(height),
+		0, gl.RGBA, gl.UNSIGNED_BYTE, pixels)
+	renderer.frame.UploadedBytes += int(width * height * bytesPerRGBAPixel)
 
 	// Store our identifier
-	io.Fonts().SetTexID(imgui.TextureID(uintptr(renderer.fontTexture)))
+	io.Fonts().SetTexID(textureID(renderer.fontTexture))
 
 	// Restore state
 	gl.BindTexture(gl.TEXTURE_2D, uint32(lastTexture))
 }
 
+// setupRenderState sets the state for rendering the draw data: alpha-blending enabled, no face culling,
+// no depth testing, scissor enabled, polygon fill, as well as the projection, the shader and the vertex layout.
+// It is applied before the first command, and again after user callbacks, which may change any state.
+func (renderer *OpenGL3) setupRenderState(drawData imgui.DrawData, fbWidth, fbHeight float32, vaoHandle uint32) {
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.Enable(gl.BLEND)
+	gl.BlendEquation(gl.FUNC_ADD)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.Disable(gl.CULL_FACE)
+	gl.Disable(gl.DEPTH_TEST)
+	gl.Enable(gl.SCISSOR_TEST)
+	gl.PolygonMode(gl.FRONT_AND_BACK, gl.FILL)
+
+	// Setup viewport, orthographic projection matrix
+	// Our visible cimgui space lies from draw_data->DisplayPos (top left) to draw_data->DisplayPos+data_data->DisplaySize (bottom right).
+	// DisplayMin is typically (0,0) for single viewport apps.
+	gl.Viewport(0, 0, int32(fbWidth), int32(fbHeight))
+	displayPos, displaySize := drawData.DisplayPos(), drawData.DisplaySize()
+	left, right := displayPos.X, displayPos.X+displaySize.X
+	top, bottom := displayPos.Y, displayPos.Y+displaySize.Y
+	orthoProjection := [4][4]float32{
+		{2.0 / (right - left), 0.0, 0.0, 0.0},
+		{0.0, 2.0 / (top - bottom), 0.0, 0.0},
+		{0.0, 0.0, -1.0, 0.0},
+		{(right + left) / (left - right), (top + bottom) / (bottom - top), 0.0, 1.0},
+	}
+	gl.UseProgram(renderer.shaderHandle)
+	gl.Uniform1i(renderer.attribLocationTex, 0)
+	gl.UniformMatrix4fv(renderer.attribLocationProjMtx, 1, false, &orthoProjection[0][0])
+	gl.BindSampler(0, 0) // Rely on combined texture/sampler state.
+
+	gl.BindVertexArray(vaoHandle)
+	gl.BindBuffer(gl.ARRAY_BUFFER, renderer.vboHandle)
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, renderer.elementsHandle)
+	gl.EnableVertexAttribArray(uint32(renderer.attribLocationPosition))
+	gl.EnableVertexAttribArray(uint32(renderer.attribLocationUV))
+	gl.EnableVertexAttribArray(uint32(renderer.attribLocationColor))
+	vertexSize, vertexOffsetPos, vertexOffsetUv, vertexOffsetCol := imgui.VertexBufferLayout()
+	gl.VertexAttribPointerWithOffset(uint32(renderer.attribLocationPosition), 2, gl.FLOAT, false, int32(vertexSize), uintptr(vertexOffsetPos))
+	gl.VertexAttribPointerWithOffset(uint32(renderer.attribLocationUV), 2, gl.FLOAT, false, int32(vertexSize), uintptr(vertexOffsetUv))
+	gl.VertexAttribPointerWithOffset(uint32(renderer.attribLocationColor), 4, gl.UNSIGNED_BYTE, true, int32(vertexSize), uintptr(vertexOffsetCol))
+}
+
+// RenderWindow renders the draw data of a secondary viewport.
+// The OpenGL context of the viewport window must be current.
+func (renderer *OpenGL3) RenderWindow(viewport imgui.Viewport) {
+	if (viewport.Flags() & imgui.ViewportFlagsNoRendererClear) == 0 {
+		renderer.PreRender([3]float32{0, 0, 0})
+	}
+	renderer.render(viewport.DrawData())
+}
+
+// CreateTexture uploads tightly packed 8-bit RGBA pixels to the graphics system.
+// The returned identifier can be used with imgui.Image and similar functions.
+func (renderer *OpenGL3) CreateTexture(pixels []uint8, width, height int) (imgui.TextureID, error) {
+	err := validateTextureData(pixels, width, height)
+	if err != nil {
+		return nil, err
+	}
+
+	var handle uint32
+	gl.GenTextures(1, &handle)
+	renderer.textures[handle] = struct{}{}
+	renderer.uploadTexture(handle, pixels, width, height)
+	return textureID(handle), nil
+}
+
+// UpdateTexture replaces the content of a texture previously created with CreateTexture.
+// The dimensions may differ from the previous content.
+func (renderer *OpenGL3) UpdateTexture(id imgui.TextureID, pixels []uint8, width, height int) error {
+	handle := textureHandle(id)
+	if _, known := renderer.textures[handle]; !known {
+		return ErrUnknownTexture
+	}
+	err := validateTextureData(pixels, width, height)
+	if err != nil {
+		return err
+	}
+
+	renderer.uploadTexture(handle, pixels, width, height)
+	return nil
+}
+
+// DeleteTexture releases a texture previously created with CreateTexture.
+func (renderer *OpenGL3) DeleteTexture(id imgui.TextureID) {
+	handle := textureHandle(id)
+	if _, known := renderer.textures[handle]; !known {
+		return
+	}
+	gl.DeleteTextures(1, &handle)
+	delete(renderer.textures, handle)
+}
+
+func (renderer *OpenGL3) uploadTexture(handle uint32, pixels []uint8, width, height int) {
+	var lastTexture int32
+	gl.GetIntegerv(gl.TEXTURE_BINDING_2D, &lastTexture)
+	gl.BindTexture(gl.TEXTURE_2D, handle)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	gl.PixelStorei(gl.UNPACK_ROW_LENGTH, 0)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, int32(width), int32(height),
+		0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(pixels))
+	gl.BindTexture(gl.TEXTURE_2D, uint32(lastTexture))
+	renderer.frame.UploadedBytes += width * height * bytesPerRGBAPixel
+}
+
 func (renderer *OpenGL3) invalidateDeviceObjects() {
 	if renderer.vboHandle != 0 {
 		gl.DeleteBuffers(1, &renderer.vboHandle)
@@ -320,9 +461,53 @@ func (renderer *OpenGL3) invalidateDeviceObjects() {
 	}
 	renderer.shaderHandle = 0
 
+	renderer.destroyFontsTexture()
+}
+
+func (renderer *OpenGL3) destroyFontsTexture() {
 	if renderer.fontTexture != 0 {
 		gl.DeleteTextures(1, &renderer.fontTexture)
 		imgui.CurrentIO().Fonts().SetTexID(nil)
 		renderer.fontTexture = 0
 	}
 }
+
+// createTimer prepares measuring the GPU time, if the driver supports timer queries.
+// They are part of OpenGL 3.3, and available as GL_ARB_timer_query before.
+func (renderer *OpenGL3) createTimer() {
+	var major, minor, count int32
+	gl.GetIntegerv(gl.MAJOR_VERSION, &major)
+	gl.GetIntegerv(gl.MINOR_VERSION, &minor)
+	gl.GetIntegerv(gl.NUM_EXTENSIONS, &count)
+	extensions := make([]string, count)
+	for i := range extensions {
+		extensions[i] = gl.GoStr(gl.GetStringi(gl.EXTENSIONS, uint32(i)))
+	}
+	if ((major < 3) || ((major == 3) && (minor < 3))) && !hasExtension(extensions, timerQueryExtension) {
+		return
+	}
+
+	var queries [timerRingSize]uint32
+	gl.GenQueries(timerRingSize, &queries[0])
+	renderer.timer = newGPUTimer(queries, timerFuncs{
+		begin: func(query uint32) { gl.BeginQuery(gl.TIME_ELAPSED, query) },
+		end:   func() { gl.EndQuery(gl.TIME_ELAPSED) },
+		available: func(query uint32) bool {
+			var available int32
+			gl.GetQueryObjectiv(query, gl.QUERY_RESULT_AVAILABLE, &available)
+			return available != 0
+		},
+		result: func(query uint32) time.Duration {
+			var nanoseconds uint64
+			gl.GetQueryObjectui64v(query, gl.QUERY_RESULT, &nanoseconds)
+			return time.Duration(nanoseconds)
+		},
+	})
+}
+
+func (renderer *OpenGL3) deleteTimer() {
+	if renderer.timer.supported {
+		gl.DeleteQueries(timerRingSize, &renderer.timer.queries[0])
+		renderer.timer = gpuTimer{}
+	}
+}
@@ -0,0 +1,18 @@
+package renderers
+
+import "time"
+
+// Stats describes the work of a renderer in one frame.
+type Stats struct {
+	// GPUTime is the time the GPU took to draw the main viewport. It is measured with timer queries
+	// and read a few frames later, so that reading it does not stall the rendering.
+	GPUTime time.Duration
+	// GPUTimeSupported tells whether the driver supports timer queries. GPUTime is zero otherwise.
+	GPUTimeSupported bool
+	// DrawCalls is the number of draw calls for all viewports.
+	DrawCalls int
+	// UploadedBytes is the size of the vertex, index and texture data that was sent to the GPU.
+	UploadedBytes int
+	// Errors lists the errors that OpenGL reported, other than the ones that end rendering.
+	Errors []ErrorCode
+}
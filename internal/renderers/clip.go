@@ -0,0 +1,39 @@
+package renderers
+
+import (
+	"github.com/AllenDang/cimgui-go"
+)
+
+// framebufferSize returns the size of the framebuffer that draw data is meant for.
+func framebufferSize(drawData imgui.DrawData) (width, height float32) {
+	displaySize := drawData.DisplaySize()
+	scale := drawData.FramebufferScale()
+	return displaySize.X * scale.X, displaySize.Y * scale.Y
+}
+
+// scissorBox projects the clip rectangle of a draw command into the framebuffer.
+// The clip rectangle is in imgui coordinates, starting at clipOffset (DrawData.DisplayPos).
+// The result is in OpenGL window coordinates, with the origin at the bottom left.
+// It returns false if nothing of the rectangle is visible.
+func scissorBox(clipRect imgui.Vec4, clipOffset, clipScale imgui.Vec2, fbWidth, fbHeight float32) (box [4]int32, visible bool) {
+	minX := (clipRect.X - clipOffset.X) * clipScale.X
+	minY := (clipRect.Y - clipOffset.Y) * clipScale.Y
+	maxX := (clipRect.Z - clipOffset.X) * clipScale.X
+	maxY := (clipRect.W - clipOffset.Y) * clipScale.Y
+	if minX < 0 {
+		minX = 0
+	}
+	if minY < 0 {
+		minY = 0
+	}
+	if maxX > fbWidth {
+		maxX = fbWidth
+	}
+	if maxY > fbHeight {
+		maxY = fbHeight
+	}
+	if (maxX <= minX) || (maxY <= minY) {
+		return box, false
+	}
+	return [4]int32{int32(minX), int32(fbHeight - maxY), int32(maxX - minX), int32(maxY - minY)}, true
+}
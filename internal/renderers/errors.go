@@ -0,0 +1,29 @@
+package renderers
+
+// StringError describes a basic error with static information.
+type StringError string
+
+// Error returns the string itself.
+func (err StringError) Error() string {
+	return string(err)
+}
+
+const (
+	// ErrInvalidTextureData is used in case pixel data does not match the given dimensions.
+	ErrInvalidTextureData = StringError("invalid texture data")
+	// ErrUnknownTexture is used in case a texture identifier was not created by the renderer.
+	ErrUnknownTexture = StringError("unknown texture")
+	// ErrOutOfMemory is used in case the graphics system ran out of memory.
+	ErrOutOfMemory = StringError("graphics system out of memory")
+	// ErrContextLost is used in case the graphics context was lost, for example due to a driver reset.
+	ErrContextLost = StringError("graphics context lost")
+)
+
+const bytesPerRGBAPixel = 4
+
+func validateTextureData(pixels []uint8, width, height int) error {
+	if (width <= 0) || (height <= 0) || (len(pixels) < width*height*bytesPerRGBAPixel) {
+		return ErrInvalidTextureData
+	}
+	return nil
+}
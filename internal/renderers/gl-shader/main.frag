@@ -7,5 +7,5 @@ out vec4 Out_Color;
 
 void main()
 {
-    Out_Color = vec4(Frag_Color.rgb, Frag_Color.a * texture(Texture, Frag_UV.st).r);
+    Out_Color = Frag_Color * texture(Texture, Frag_UV.st);
 }
@@ -0,0 +1,45 @@
+package renderers
+
+import "fmt"
+
+// These are the error codes of OpenGL that the renderers treat specially. They are the same for all versions.
+const (
+	glNoError     = 0
+	glOutOfMemory = 0x0505
+	glContextLost = 0x0507
+)
+
+// maxErrorFlags bounds the reading of error flags, as some drivers keep reporting errors for a lost context.
+const maxErrorFlags = 16
+
+// ErrorCode is an error reported by OpenGL that does not prevent further rendering, such as GL_INVALID_OPERATION.
+// It typically is caused by a bug in the renderer or in a draw callback.
+type ErrorCode uint32
+
+// Error returns the code in hexadecimal notation, as in the OpenGL headers.
+func (code ErrorCode) Error() string {
+	return fmt.Sprintf("OpenGL error 0x%04X", uint32(code))
+}
+
+// collectErrors reads all error flags of OpenGL, as each call of getError only clears one of them.
+// The first error that prevents further rendering becomes the failure; the others are recorded in the stats.
+func collectErrors(getError func() uint32, failure *error, stats *Stats) {
+	for i := 0; i < maxErrorFlags; i++ {
+		code := getError()
+		var err error
+		switch code {
+		case glNoError:
+			return
+		case glOutOfMemory:
+			err = ErrOutOfMemory
+		case glContextLost:
+			err = ErrContextLost
+		default:
+			stats.Errors = append(stats.Errors, ErrorCode(code))
+			continue
+		}
+		if *failure == nil {
+			*failure = err
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package renderers
+
+import "time"
+
+// timerQueryExtension provides GL_TIME_ELAPSED queries before OpenGL 3.3.
+const timerQueryExtension = "GL_ARB_timer_query"
+
+// timerRingSize is the number of queries in flight. A result is read at most this many frames after it was measured.
+const timerRingSize = 4
+
+// timerFuncs are the query functions of the OpenGL binding of a renderer.
+type timerFuncs struct {
+	begin     func(query uint32)
+	end       func()
+	available func(query uint32) bool
+	result    func(query uint32) time.Duration
+}
+
+// gpuTimer measures the time the GPU takes for a draw pass with a ring of GL_TIME_ELAPSED queries.
+// A query is only reused once its result was read, and results are only read once they are available,
+// so that measuring never waits for the GPU. Frames are left out while all queries are in flight.
+type gpuTimer struct {
+	funcs     timerFuncs
+	supported bool
+	queries   [timerRingSize]uint32
+	pending   [timerRingSize]bool
+	next      int
+	active    bool
+	elapsed   time.Duration
+}
+
+func newGPUTimer(queries [timerRingSize]uint32, funcs timerFuncs) gpuTimer {
+	return gpuTimer{funcs: funcs, supported: true, queries: queries}
+}
+
+// begin starts measuring, if a query is free.
+func (timer *gpuTimer) begin() {
+	if !timer.supported || timer.pending[timer.next] {
+		return
+	}
+	timer.funcs.begin(timer.queries[timer.next])
+	timer.active = true
+}
+
+// end stops measuring.
+func (timer *gpuTimer) end() {
+	if !timer.active {
+		return
+	}
+	timer.funcs.end()
+	timer.active = false
+	timer.pending[timer.next] = true
+	timer.next = (timer.next + 1) % timerRingSize
+}
+
+// poll reads the available results, the oldest first, and returns the most recent one.
+func (timer *gpuTimer) poll() time.Duration {
+	for i := 0; i < timerRingSize; i++ {
+		index := (timer.next + i) % timerRingSize
+		if !timer.pending[index] {
+			continue
+		}
+		// Queries complete in order, so the later ones are not available either.
+		if !timer.funcs.available(timer.queries[index]) {
+			break
+		}
+		timer.elapsed = timer.funcs.result(timer.queries[index])
+		timer.pending[index] = false
+	}
+	return timer.elapsed
+}
+
+// hasExtension returns true if the list of extension names contains the name.
+func hasExtension(extensions []string, name string) bool {
+	for _, extension := range extensions {
+		if extension == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,19 @@
+package renderers
+
+import (
+	"unsafe"
+
+	"github.com/AllenDang/cimgui-go"
+)
+
+// textureID returns the identifier for imgui of a texture handle. The handle is stored as the value of the pointer.
+// The bits are copied instead of converted, as the pointer checks of the race detector reject converting small integers.
+func textureID(handle uint32) imgui.TextureID {
+	value := uintptr(handle)
+	return *(*imgui.TextureID)(unsafe.Pointer(&value))
+}
+
+// textureHandle returns the texture handle stored in an identifier of imgui.
+func textureHandle(id imgui.TextureID) uint32 {
+	return uint32(uintptr(id))
+}
@@ -0,0 +1,156 @@
+//go:build linux
+
+package headless
+
+// #cgo pkg-config: egl gl
+// #include <stdlib.h>
+// #include <EGL/egl.h>
+// #include <EGL/eglext.h>
+// #include <GL/gl.h>
+//
+// // surfacelessDisplay returns the display of the Mesa platform that needs neither X11 nor Wayland,
+// // or the default display if that platform is not available.
+// static EGLDisplay surfacelessDisplay() {
+// 	PFNEGLGETPLATFORMDISPLAYEXTPROC getPlatformDisplay =
+// 		(PFNEGLGETPLATFORMDISPLAYEXTPROC)eglGetProcAddress("eglGetPlatformDisplayEXT");
+// 	if (getPlatformDisplay != NULL) {
+// 		EGLDisplay display = getPlatformDisplay(EGL_PLATFORM_SURFACELESS_MESA, EGL_DEFAULT_DISPLAY, NULL);
+// 		if (display != EGL_NO_DISPLAY) {
+// 			return display;
+// 		}
+// 	}
+// 	return eglGetDisplay(EGL_DEFAULT_DISPLAY);
+// }
+//
+// static EGLBoolean chooseConfig(EGLDisplay display, EGLConfig *config) {
+// 	const EGLint attributes[] = {
+// 		EGL_SURFACE_TYPE, EGL_PBUFFER_BIT,
+// 		EGL_RENDERABLE_TYPE, EGL_OPENGL_BIT,
+// 		EGL_RED_SIZE, 8, EGL_GREEN_SIZE, 8, EGL_BLUE_SIZE, 8, EGL_ALPHA_SIZE, 8,
+// 		EGL_NONE,
+// 	};
+// 	EGLint count = 0;
+// 	return eglChooseConfig(display, attributes, config, 1, &count) && (count > 0);
+// }
+//
+// static EGLSurface createSurface(EGLDisplay display, EGLConfig config, EGLint width, EGLint height) {
+// 	const EGLint attributes[] = {EGL_WIDTH, width, EGL_HEIGHT, height, EGL_NONE};
+// 	return eglCreatePbufferSurface(display, config, attributes);
+// }
+//
+// static EGLContext createContext(EGLDisplay display, EGLConfig config, EGLint major, EGLint minor, EGLBoolean core) {
+// 	const EGLint attributes[] = {
+// 		EGL_CONTEXT_MAJOR_VERSION, major,
+// 		EGL_CONTEXT_MINOR_VERSION, minor,
+// 		EGL_CONTEXT_OPENGL_PROFILE_MASK,
+// 		core ? EGL_CONTEXT_OPENGL_CORE_PROFILE_BIT : EGL_CONTEXT_OPENGL_COMPATIBILITY_PROFILE_BIT,
+// 		EGL_NONE,
+// 	};
+// 	if (!eglBindAPI(EGL_OPENGL_API)) {
+// 		return EGL_NO_CONTEXT;
+// 	}
+// 	return eglCreateContext(display, config, EGL_NO_CONTEXT, attributes);
+// }
+//
+// static void readPixels(GLint width, GLint height, void *pixels) {
+// 	glPixelStorei(GL_PACK_ALIGNMENT, 1);
+// 	glReadPixels(0, 0, width, height, GL_RGBA, GL_UNSIGNED_BYTE, pixels);
+// }
+import "C"
+
+import (
+	"fmt"
+	"sync"
+)
+
+// The display is initialized once and kept for all contexts. Terminating and initializing it again
+// corrupts the heap with Mesa's llvmpipe driver.
+var (
+	displayOnce sync.Once
+	display     C.EGLDisplay
+	displayErr  error
+)
+
+func initDisplay() (C.EGLDisplay, error) {
+	displayOnce.Do(func() {
+		display = C.surfacelessDisplay()
+		if display == C.EGLDisplay(C.EGL_NO_DISPLAY) {
+			displayErr = ErrUnsupported
+		} else if C.eglInitialize(display, nil, nil) == C.EGL_FALSE {
+			displayErr = fmt.Errorf("%w: %v", ErrUnsupported, eglError())
+		}
+	})
+	return display, displayErr
+}
+
+// Context is an OpenGL context that renders into a surface in memory, of a fixed size.
+type Context struct {
+	display C.EGLDisplay
+	surface C.EGLSurface
+	context C.EGLContext
+	width   int
+	height  int
+}
+
+// NewContext creates a context of the given OpenGL version and makes it current on the calling thread.
+// Versions from 3.2 on are created with the core profile, earlier ones with the compatibility profile.
+// The calling goroutine has to be locked to its thread for as long as the context is used.
+func NewContext(width, height int, major, minor int) (*Context, error) {
+	display, err := initDisplay()
+	if err != nil {
+		return nil, err
+	}
+	context := &Context{display: display, width: width, height: height}
+
+	var config C.EGLConfig
+	if C.chooseConfig(display, &config) == C.EGL_FALSE {
+		return nil, context.fail("no configuration for an RGBA surface")
+	}
+	context.surface = C.createSurface(display, config, C.EGLint(width), C.EGLint(height))
+	if context.surface == C.EGLSurface(C.EGL_NO_SURFACE) {
+		return nil, context.fail("failed to create surface")
+	}
+	core := C.EGLBoolean(C.EGL_FALSE)
+	if (major > 3) || ((major == 3) && (minor >= 2)) {
+		core = C.EGL_TRUE
+	}
+	context.context = C.createContext(display, config, C.EGLint(major), C.EGLint(minor), core)
+	if context.context == C.EGLContext(C.EGL_NO_CONTEXT) {
+		return nil, context.fail(fmt.Sprintf("failed to create OpenGL %d.%d context", major, minor))
+	}
+	if C.eglMakeCurrent(display, context.surface, context.surface, context.context) == C.EGL_FALSE {
+		return nil, context.fail("failed to make context current")
+	}
+	return context, nil
+}
+
+// Destroy releases the context and its surface. The display stays initialized for further contexts.
+func (context *Context) Destroy() {
+	C.eglMakeCurrent(context.display, nil, nil, nil)
+	if context.context != nil {
+		C.eglDestroyContext(context.display, context.context)
+	}
+	if context.surface != nil {
+		C.eglDestroySurface(context.display, context.surface)
+	}
+}
+
+// Pixels returns the content of the surface as tightly packed 8-bit RGBA pixels, with the bottom row first.
+func (context *Context) Pixels() []uint8 {
+	pixels := C.malloc(C.size_t(context.width * context.height * 4))
+	defer C.free(pixels)
+	C.readPixels(C.GLint(context.width), C.GLint(context.height), pixels)
+	return C.GoBytes(pixels, C.int(context.width*context.height*4))
+}
+
+// fail destroys the partially created context, and returns the error of EGL that made its creation fail.
+func (context *Context) fail(message string) error {
+	err := fmt.Errorf("%s: %w", message, eglError())
+	context.Destroy()
+	return err
+}
+
+// eglError describes the last error of EGL on the calling thread.
+func eglError() error {
+	return fmt.Errorf("EGL error 0x%04X", uint32(C.eglGetError()))
+}
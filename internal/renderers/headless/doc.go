@@ -0,0 +1,5 @@
+// Package headless provides OpenGL contexts that render without a window, into a surface in memory.
+// They allow to test the renderers on machines without a display, such as build servers, where a
+// software implementation of OpenGL like Mesa's llvmpipe is usually available.
+// Contexts are only supported on Linux, through EGL; on other systems, NewContext returns ErrUnsupported.
+package headless
@@ -0,0 +1,12 @@
+package headless
+
+// StringError describes a basic error with static information.
+type StringError string
+
+// Error returns the string itself.
+func (err StringError) Error() string {
+	return string(err)
+}
+
+// ErrUnsupported is used in case the system provides no means to create a context without a window.
+const ErrUnsupported = StringError("headless contexts not supported")
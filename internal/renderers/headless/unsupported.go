@@ -0,0 +1,19 @@
+//go:build !linux
+
+package headless
+
+// Context is an OpenGL context that renders into a surface in memory, of a fixed size.
+type Context struct{}
+
+// NewContext returns ErrUnsupported, as only EGL on Linux provides contexts without a window.
+func NewContext(width, height int, major, minor int) (*Context, error) {
+	return nil, ErrUnsupported
+}
+
+// Destroy does nothing.
+func (context *Context) Destroy() {}
+
+// Pixels returns nil.
+func (context *Context) Pixels() []uint8 {
+	return nil
+}
@@ -0,0 +1,86 @@
+package renderers
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/AllenDang/cimgui-go"
+	"github.com/ptxmac/cimgui-go-examples/internal/renderers/headless"
+)
+
+// renderer is the part of the renderers that the tests use.
+type renderer interface {
+	NewFrame()
+	PreRender(clearColor [3]float32)
+	Render(drawData imgui.DrawData)
+	Err() error
+	Dispose()
+}
+
+// newHeadlessContext creates an OpenGL context of the size of the mesh, or skips the test if there is none.
+// The test stays on the thread of the context until it ends.
+func newHeadlessContext(t *testing.T, major, minor int) *headless.Context {
+	t.Helper()
+	runtime.LockOSThread()
+	t.Cleanup(runtime.UnlockOSThread)
+	context, err := headless.NewContext(meshWidth, meshHeight, major, minor)
+	if err != nil {
+		t.Skipf("no OpenGL %d.%d context available: %v", major, minor, err)
+	}
+	t.Cleanup(context.Destroy)
+	return context
+}
+
+// renderLargeMesh renders the mesh and checks that every rectangle ended up at its place.
+// It must run on the goroutine of the test, which holds the context; subtests run on goroutines of their own.
+func renderLargeMesh(t *testing.T, context *headless.Context, r renderer, path string) {
+	t.Helper()
+	r.NewFrame()
+	drawData := largeMesh()
+	r.PreRender([3]float32{0, 0, 0})
+	r.Render(drawData)
+	if err := r.Err(); err != nil {
+		t.Fatalf("%s: rendering failed: %v", path, err)
+	}
+
+	pixels := context.Pixels()
+	pixel := func(x, y int) uint8 {
+		return pixels[((meshHeight-1-y)*meshWidth+x)*4] // Red channel, with the bottom row first
+	}
+	failures := 0
+	for k := 0; (k < rectCount) && (failures < 10); k++ {
+		x, y := rectOrigin(k)
+		if (pixel(x, y) != 0xFF) || (pixel(x+1, y+1) != 0xFF) || (pixel(x+2, y+2) != 0) {
+			t.Errorf("%s: rectangle %d at (%d, %d) not rendered in place", path, k, x, y)
+			failures++
+		}
+	}
+}
+
+func TestOpenGL2RendersLargeMeshes(t *testing.T) {
+	io := newImguiContext(t)
+	context := newHeadlessContext(t, 2, 1)
+	r, err := NewOpenGL2(io)
+	if err != nil {
+		t.Fatalf("failed to create renderer: %v", err)
+	}
+	defer r.Dispose()
+
+	if r.vboHandle != 0 {
+		renderLargeMesh(t, context, r, "buffer objects")
+	}
+	r.deleteBuffers()
+	renderLargeMesh(t, context, r, "client arrays")
+}
+
+func TestOpenGL3RendersLargeMeshes(t *testing.T) {
+	io := newImguiContext(t)
+	context := newHeadlessContext(t, 3, 2)
+	r, err := NewOpenGL3(io)
+	if err != nil {
+		t.Fatalf("failed to create renderer: %v", err)
+	}
+	defer r.Dispose()
+
+	renderLargeMesh(t, context, r, "buffer objects")
+}
@@ -0,0 +1,43 @@
+package renderers
+
+// #cgo windows LDFLAGS: -lopengl32
+// #cgo darwin CFLAGS: -DGL_SILENCE_DEPRECATION
+// #cgo darwin LDFLAGS: -framework OpenGL
+// #cgo linux freebsd openbsd pkg-config: gl
+// #include <stdint.h>
+// #if defined(__APPLE__)
+// 	#include <OpenGL/gl.h>
+// #else
+// 	#if defined(_WIN32)
+// 		#define WIN32_LEAN_AND_MEAN 1
+// 		#include <windows.h>
+// 	#endif
+// 	#include <GL/gl.h>
+// #endif
+//
+// // setVertexPointers takes the pointers as integers, as offsets into a buffer object are no valid pointers of Go.
+// static void setVertexPointers(GLsizei stride, uintptr_t pos, uintptr_t uv, uintptr_t col) {
+// 	glVertexPointer(2, GL_FLOAT, stride, (const void *)pos);
+// 	glTexCoordPointer(2, GL_FLOAT, stride, (const void *)uv);
+// 	glColorPointer(4, GL_UNSIGNED_BYTE, stride, (const void *)col);
+// }
+import "C"
+
+import (
+	"github.com/AllenDang/cimgui-go"
+)
+
+// setVertexPointers points the fixed-function vertex arrays to the vertices starting at the given index.
+// With a vertex buffer address of zero, the pointers are offsets into the bound vertex buffer object.
+func setVertexPointers(vertexBuffer uintptr, vertexOffset int) {
+	vertexSize, _, _, _ := imgui.VertexBufferLayout()
+	pos, uv, col := vertexPointers(vertexBuffer, vertexOffset)
+	C.setVertexPointers(C.GLsizei(vertexSize), C.uintptr_t(pos), C.uintptr_t(uv), C.uintptr_t(col))
+}
+
+// vertexPointers returns the addresses of the position, texture coordinates, and color of the vertex at the given index.
+func vertexPointers(vertexBuffer uintptr, vertexOffset int) (pos, uv, col uintptr) {
+	vertexSize, posOffset, uvOffset, colOffset := imgui.VertexBufferLayout()
+	vertex := vertexBuffer + uintptr(vertexOffset*vertexSize)
+	return vertex + uintptr(posOffset), vertex + uintptr(uvOffset), vertex + uintptr(colOffset)
+}
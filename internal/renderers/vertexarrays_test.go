@@ -0,0 +1,146 @@
+package renderers
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/AllenDang/cimgui-go"
+)
+
+const (
+	meshWidth   = 800
+	meshHeight  = 600
+	rectsPerRow = 200
+	rectCount   = 20000 // Four vertices each, which exceeds the range of 16-bit indices
+)
+
+// newImguiContext creates an imgui context for a display of the size of the mesh.
+func newImguiContext(t *testing.T) imgui.IO {
+	t.Helper()
+	context := imgui.CreateContext()
+	t.Cleanup(context.Destroy)
+	io := imgui.CurrentIO()
+	io.SetDisplaySize(imgui.Vec2{X: meshWidth, Y: meshHeight})
+	io.SetBackendFlags(io.BackendFlags() | imgui.BackendFlagsRendererHasVtxOffset)
+	io.Fonts().Build()
+	return io
+}
+
+// rectOrigin returns the top left corner of rectangle k of the mesh, which covers a square of size 2.
+func rectOrigin(k int) (x, y int) {
+	return (k % rectsPerRow) * 4, (k / rectsPerRow) * 4
+}
+
+// largeMesh builds a frame with more vertices than 16-bit indices can address, and returns its draw data.
+func largeMesh() imgui.DrawData {
+	imgui.NewFrame()
+	list := imgui.ForegroundDrawListNil()
+	for k := 0; k < rectCount; k++ {
+		x, y := rectOrigin(k)
+		min := imgui.Vec2{X: float32(x), Y: float32(y)}
+		list.AddRectFilled(min, imgui.Vec2{X: min.X + 2, Y: min.Y + 2}, 0xFFFFFFFF)
+	}
+	imgui.Render()
+	return imgui.CurrentDrawData()
+}
+
+// largeDrawList returns the draw list of a frame with a large mesh.
+func largeDrawList(t *testing.T) imgui.DrawList {
+	t.Helper()
+	newImguiContext(t)
+	lists := largeMesh().CommandLists()
+	if len(lists) == 0 {
+		t.Fatal("draw data has no command lists")
+	}
+	return lists[len(lists)-1]
+}
+
+// rectCommands returns the commands of the list that draw something, skipping the empty trailing one.
+func rectCommands(list imgui.DrawList) []imgui.DrawCmd {
+	var commands []imgui.DrawCmd
+	for _, command := range list.Commands() {
+		if command.ElemCount() > 0 {
+			commands = append(commands, command)
+		}
+	}
+	return commands
+}
+
+func TestLargeMeshesAreSplitIntoCommandsWithVertexOffsets(t *testing.T) {
+	if imgui.IndexBufferLayout() != 2 {
+		t.Skip("imgui is built with 32-bit indices")
+	}
+	list := largeDrawList(t)
+	commands := rectCommands(list)
+	if len(commands) < 2 {
+		t.Fatalf("draw list has %d commands, expected the mesh to be split", len(commands))
+	}
+
+	indexBuffer, indexBufferSize := list.GetIndexBuffer()
+	indices := unsafe.Slice((*uint16)(indexBuffer), indexBufferSize/2)
+	vertexCount := 0
+	lastOffset := -1
+	for i, command := range commands {
+		offset := int(command.VtxOffset())
+		if offset <= lastOffset {
+			t.Errorf("command %d has vertex offset %d, after %d", i, offset, lastOffset)
+		}
+		lastOffset = offset
+		commandIndices := indices[command.IdxOffset() : command.IdxOffset()+command.ElemCount()]
+		for _, index := range commandIndices {
+			if int(index)+offset+1 > vertexCount {
+				vertexCount = int(index) + offset + 1
+			}
+		}
+	}
+	if commands[0].VtxOffset() != 0 {
+		t.Errorf("first command has vertex offset %d", commands[0].VtxOffset())
+	}
+	if vertexCount != rectCount*4 {
+		t.Errorf("commands reference %d vertices, expected %d", vertexCount, rectCount*4)
+	}
+}
+
+func TestVertexPointersAreRebasedToTheVertexOffset(t *testing.T) {
+	list := largeDrawList(t)
+	vertexBuffer, _ := list.GetVertexBuffer()
+	indexBuffer, indexBufferSize := list.GetIndexBuffer()
+	indexSize := imgui.IndexBufferLayout()
+	vertexSize, posOffset, uvOffset, colOffset := imgui.VertexBufferLayout()
+
+	for i, command := range rectCommands(list) {
+		offset := int(command.VtxOffset())
+
+		// Buffer objects: the pointers are byte offsets from the start of the bound buffer.
+		pos, uv, col := vertexPointers(0, offset)
+		if (pos != uintptr(offset*vertexSize+posOffset)) ||
+			(uv != uintptr(offset*vertexSize+uvOffset)) ||
+			(col != uintptr(offset*vertexSize+colOffset)) {
+			t.Errorf("command %d: buffer offsets %d, %d, %d for vertex offset %d", i, pos, uv, col, offset)
+		}
+
+		// Client arrays: the indices of the command, applied to the rebased pointer, reach the vertices of its rectangles.
+		pos, _, _ = vertexPointers(uintptr(vertexBuffer), offset)
+		positions := unsafe.Add(vertexBuffer, pos-uintptr(vertexBuffer))
+		first := int(command.IdxOffset())
+		for _, at := range []int{first, first + int(command.ElemCount()) - 1} {
+			if at*indexSize >= indexBufferSize {
+				t.Fatalf("command %d: index %d out of range", i, at)
+			}
+			index := readIndex(indexBuffer, indexSize, at)
+			vertex := (*imgui.Vec2)(unsafe.Add(positions, index*vertexSize))
+			rect := (offset + index) / 4
+			x, y := rectOrigin(rect)
+			if (vertex.X < float32(x)) || (vertex.X > float32(x+2)) || (vertex.Y < float32(y)) || (vertex.Y > float32(y+2)) {
+				t.Errorf("command %d: index %d points to %v, outside of rectangle %d", i, index, *vertex, rect)
+			}
+		}
+	}
+}
+
+func readIndex(indexBuffer unsafe.Pointer, indexSize int, at int) int {
+	if indexSize == 4 {
+		return int(unsafe.Slice((*uint32)(indexBuffer), at+1)[at])
+	}
+	return int(unsafe.Slice((*uint16)(indexBuffer), at+1)[at])
+}
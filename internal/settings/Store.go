@@ -0,0 +1,229 @@
+package settings
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/AllenDang/cimgui-go"
+)
+
+// Migration upgrades the sections of the settings by one version.
+// Sections may be added, removed, or rewritten in place.
+type Migration func(sections map[string]json.RawMessage) error
+
+// Config describes the location and the format of the settings.
+type Config struct {
+	// IniPath is the file of the imgui ini data. If empty, the ini data is kept in memory only.
+	IniPath string
+	// Path is the JSON file of the application settings. If empty, the settings are kept in memory only.
+	Path string
+	// Version is the current version of the application settings.
+	Version int
+	// Migrations upgrade the application settings, keyed by the version they upgrade from.
+	// Versions without a migration are upgraded without change.
+	Migrations map[int]Migration
+}
+
+// document is the content of the JSON file.
+type document struct {
+	Version  int                        `json:"version"`
+	Sections map[string]json.RawMessage `json:"sections"`
+}
+
+// Store keeps the imgui ini data and the settings of the application.
+// Settings are organized in sections, each being a JSON value of its own.
+// A Store must only be used from the thread that runs the frame loop.
+type Store struct {
+	config     Config
+	sections   map[string]json.RawMessage
+	registered map[string]interface{}
+}
+
+// NewStore returns a store for given configuration. It takes over the ini handling from imgui,
+// which means that no imgui.ini is written to the working directory anymore.
+// Load must be called before the first frame to restore the settings of the previous session.
+func NewStore(config Config) *Store {
+	disableIniFile(imgui.CurrentIO())
+	return &Store{
+		config:     config,
+		sections:   make(map[string]json.RawMessage),
+		registered: make(map[string]interface{}),
+	}
+}
+
+// Load reads the imgui ini data and the application settings, and migrates the latter to the current version.
+// Missing files are not an error, the settings keep their defaults in that case.
+func (store *Store) Load() error {
+	err := store.loadIni()
+	if err != nil {
+		return err
+	}
+	if store.config.Path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(store.config.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	var doc document
+	err = json.Unmarshal(data, &doc)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", store.config.Path, err)
+	}
+	if doc.Sections == nil {
+		doc.Sections = make(map[string]json.RawMessage)
+	}
+	err = store.migrate(&doc)
+	if err != nil {
+		return err
+	}
+	store.sections = doc.Sections
+	for key, value := range store.registered {
+		err = store.decode(key, value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (store *Store) loadIni() error {
+	if store.config.IniPath == "" {
+		return nil
+	}
+	data, err := os.ReadFile(store.config.IniPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	imgui.LoadIniSettingsFromMemory(string(data))
+	return nil
+}
+
+func (store *Store) migrate(doc *document) error {
+	if doc.Version > store.config.Version {
+		return fmt.Errorf("%w: %d instead of %d", ErrNewerVersion, doc.Version, store.config.Version)
+	}
+	for ; doc.Version < store.config.Version; doc.Version++ {
+		migration, exists := store.config.Migrations[doc.Version]
+		if !exists {
+			continue
+		}
+		err := migration(doc.Sections)
+		if err != nil {
+			return fmt.Errorf("failed to migrate settings from version %d: %w", doc.Version, err)
+		}
+	}
+	return nil
+}
+
+// Register binds a section to a value, typically a pointer to a struct.
+// The value is set from the loaded settings, now and with every following Load.
+// Save stores the then current content of the value.
+func (store *Store) Register(key string, value interface{}) error {
+	store.registered[key] = value
+	return store.decode(key, value)
+}
+
+// Get sets the value from the section of given key. It returns false if the section does not exist.
+func (store *Store) Get(key string, value interface{}) (bool, error) {
+	if _, exists := store.sections[key]; !exists {
+		return false, nil
+	}
+	return true, store.decode(key, value)
+}
+
+// Set replaces the section of given key with the value.
+func (store *Store) Set(key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode settings %s: %w", key, err)
+	}
+	store.sections[key] = data
+	return nil
+}
+
+func (store *Store) decode(key string, value interface{}) error {
+	data, exists := store.sections[key]
+	if !exists {
+		return nil
+	}
+	err := json.Unmarshal(data, value)
+	if err != nil {
+		return fmt.Errorf("failed to decode settings %s: %w", key, err)
+	}
+	return nil
+}
+
+// SaveIniIfWanted writes the imgui ini data if imgui requests it.
+// imgui requests this a short time after windows have been moved or resized.
+func (store *Store) SaveIniIfWanted() error {
+	io := imgui.CurrentIO()
+	if !io.WantSaveIniSettings() {
+		return nil
+	}
+	io.SetWantSaveIniSettings(false)
+	return store.saveIni()
+}
+
+// Save writes the imgui ini data and the application settings.
+func (store *Store) Save() error {
+	err := store.saveIni()
+	if err != nil {
+		return err
+	}
+	for key, value := range store.registered {
+		err = store.Set(key, value)
+		if err != nil {
+			return err
+		}
+	}
+	if store.config.Path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(document{Version: store.config.Version, Sections: store.sections}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(store.config.Path, data)
+}
+
+func (store *Store) saveIni() error {
+	if store.config.IniPath == "" {
+		return nil
+	}
+	return writeFile(store.config.IniPath, []byte(imgui.SaveIniSettingsToMemory()))
+}
+
+// writeFile replaces the file with given data. The data is written to a temporary file first,
+// so that an interrupted write does not destroy the previous content.
+func writeFile(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+	}
+	return err
+}
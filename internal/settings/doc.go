@@ -0,0 +1,6 @@
+// Package settings persists the settings of an application between sessions.
+// It stores the ini data of imgui, such as window positions and docking layout,
+// at a configurable location instead of the working directory, and keeps the
+// settings of the application itself in a versioned JSON file next to it.
+// Settings of older versions are upgraded with migration functions when loaded.
+package settings
@@ -0,0 +1,14 @@
+package settings
+
+// StringError describes a basic error with static information.
+type StringError string
+
+// Error returns the string itself.
+func (err StringError) Error() string {
+	return string(err)
+}
+
+const (
+	// ErrNewerVersion is returned when loading settings that were written by a newer version of the application.
+	ErrNewerVersion = StringError("settings have a newer version")
+)
@@ -0,0 +1,17 @@
+package settings
+
+// #include "ini.h"
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/AllenDang/cimgui-go"
+)
+
+// disableIniFile prevents imgui from reading and writing its ini file by itself.
+// imgui.IO.SetIniFilename can not be used for this, as it can not set a null pointer
+// and releases the string while imgui keeps referring to it.
+func disableIniFile(io imgui.IO) {
+	C.disableIniFile((*C.ImGuiIOPrefix)(unsafe.Pointer(io)))
+}
@@ -0,0 +1,21 @@
+// Declaration of the leading fields of ImGuiIO, mirroring the layout of cimgui.h
+// of the imgui version used by github.com/AllenDang/cimgui-go.
+#ifndef SETTINGS_INI_H
+#define SETTINGS_INI_H
+
+#include <stddef.h>
+
+typedef struct ImGuiIOPrefix {
+	int ConfigFlags;
+	int BackendFlags;
+	struct { float x, y; } DisplaySize;
+	float DeltaTime;
+	float IniSavingRate;
+	const char *IniFilename;
+} ImGuiIOPrefix;
+
+static inline void disableIniFile(ImGuiIOPrefix *io) {
+	io->IniFilename = NULL;
+}
+
+#endif
@@ -0,0 +1,207 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/AllenDang/cimgui-go"
+
+	"github.com/ptxmac/cimgui-go-examples/internal/dispatch"
+)
+
+const (
+	statusBarName         = "##TaskStatusBar"
+	windowName            = "Tasks"
+	statusBarProgressSize = 160
+	// statusBarLinger is how long the status bar stays after the last task finished, so that it does not flicker
+	// between tasks that follow each other, and the end of a task can be noticed.
+	statusBarLinger = 3 * time.Second
+)
+
+// Manager starts tasks and keeps the running and the failed ones for display.
+// Start and Close may be called from any goroutine; the methods that build the user interface
+// must only be called from the thread that runs the frame loop.
+type Manager struct {
+	dispatcher *dispatch.Dispatcher
+	ctx        context.Context
+	cancel     context.CancelFunc
+	running    sync.WaitGroup
+
+	mutex  sync.Mutex
+	tasks  []*Task
+	nextID int32
+	closed bool
+
+	windowOpen bool
+	// lastActive is the time of the last frame that had tasks to show.
+	lastActive time.Time
+}
+
+// NewManager returns a manager that hands the finished tasks to the frame loop with given dispatcher.
+func NewManager(dispatcher *dispatch.Dispatcher) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		dispatcher: dispatcher,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// Start runs the function on a new goroutine. Once the function returned, done is called with the task
+// on the thread of the frame loop, where it may apply the result to the state of the user interface.
+// Tasks that succeed or are canceled are removed from the display; failed tasks stay until dismissed.
+// done may be nil. After Close, the function is not run and the returned task fails with ErrClosed.
+func (manager *Manager) Start(name string, run Func, done func(task *Task)) *Task {
+	ctx, cancel := context.WithCancel(manager.ctx)
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	manager.nextID++
+	task := newTask(manager.nextID, name, cancel)
+	if manager.closed {
+		cancel()
+		task.finish(ErrClosed)
+		return task
+	}
+	manager.tasks = append(manager.tasks, task)
+	manager.running.Add(1)
+	go func() {
+		defer manager.running.Done()
+		defer cancel()
+		task.finish(run(ctx, task))
+		manager.dispatcher.Post(func() {
+			err := task.Err()
+			if (err == nil) || errors.Is(err, context.Canceled) {
+				manager.remove(task)
+			}
+			if done != nil {
+				done(task)
+			}
+		})
+	}()
+	return task
+}
+
+// Tasks returns the running and the failed tasks, in the order they were started.
+func (manager *Manager) Tasks() []*Task {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	return append([]*Task(nil), manager.tasks...)
+}
+
+// Close cancels all tasks and waits for their functions to return. Tasks started afterwards fail with ErrClosed.
+func (manager *Manager) Close() {
+	manager.mutex.Lock()
+	manager.closed = true
+	manager.mutex.Unlock()
+	manager.cancel()
+	manager.running.Wait()
+}
+
+func (manager *Manager) remove(task *Task) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	for i, candidate := range manager.tasks {
+		if candidate == task {
+			manager.tasks = append(manager.tasks[:i], manager.tasks[i+1:]...)
+			return
+		}
+	}
+}
+
+// StatusBar adds a status bar at the bottom of the main viewport, which shows the progress of the oldest
+// running task. The bar is only shown while there are running or failed tasks, and for a few seconds after
+// the last of them is gone, so that it takes no room from the application otherwise.
+// It must be called before a dock space over the main viewport is submitted,
+// so that the dock space leaves room for the bar.
+func (manager *Manager) StatusBar() {
+	tasks := manager.Tasks()
+	now := time.Now()
+	if len(tasks) > 0 {
+		manager.lastActive = now
+	} else if now.Sub(manager.lastActive) > statusBarLinger {
+		return
+	}
+	flags := imgui.WindowFlags(imgui.WindowFlagsNoScrollbar | imgui.WindowFlagsNoSavedSettings | imgui.WindowFlagsMenuBar)
+	visible := imgui.InternalBeginViewportSideBar(statusBarName, imgui.MainViewport(), imgui.DirDown, imgui.FrameHeight(), flags)
+	if visible && imgui.BeginMenuBar() {
+		var running []*Task
+		failed := 0
+		for _, task := range tasks {
+			if !task.Finished() {
+				running = append(running, task)
+			} else if task.Err() != nil {
+				failed++
+			}
+		}
+		if len(running) == 0 {
+			imgui.TextDisabled("Ready")
+		} else {
+			imgui.TextUnformatted(running[0].Name())
+			progressBar(running[0], imgui.Vec2{X: statusBarProgressSize})
+			if len(running) > 1 {
+				imgui.TextDisabled(fmt.Sprintf("+%d more", len(running)-1))
+			}
+		}
+		if failed > 0 {
+			imgui.TextColored(errorColor, fmt.Sprintf("%d failed", failed))
+		}
+		imgui.Separator()
+		if imgui.MenuItemBoolV(windowName, "", manager.windowOpen, true) {
+			manager.windowOpen = !manager.windowOpen
+		}
+		imgui.EndMenuBar()
+	}
+	imgui.End()
+}
+
+// Window shows the window that lists all tasks, if it was opened from the status bar.
+func (manager *Manager) Window() {
+	if !manager.windowOpen {
+		return
+	}
+	if imgui.BeginV(windowName, &manager.windowOpen, 0) {
+		tasks := manager.Tasks()
+		if len(tasks) == 0 {
+			imgui.TextDisabled("No tasks are running.")
+		}
+		buttonWidth := imgui.CalcTextSize("Dismiss").X + imgui.CurrentStyle().FramePadding().X*2
+		for _, task := range tasks {
+			imgui.PushIDInt(task.id)
+			imgui.TextUnformatted(task.Name())
+			width := imgui.ContentRegionAvail().X - buttonWidth - imgui.CurrentStyle().ItemSpacing().X
+			if err := task.Err(); err != nil {
+				imgui.TextColored(errorColor, err.Error())
+				imgui.SameLine()
+				if imgui.ButtonV("Dismiss", imgui.Vec2{X: buttonWidth}) {
+					manager.remove(task)
+				}
+			} else {
+				progressBar(task, imgui.Vec2{X: width})
+				imgui.SameLine()
+				if imgui.ButtonV("Cancel", imgui.Vec2{X: buttonWidth}) {
+					task.Cancel()
+				}
+			}
+			imgui.PopID()
+		}
+	}
+	imgui.End()
+}
+
+var errorColor = imgui.Vec4{X: 1, Y: 0.4, Z: 0.4, W: 1}
+
+// progressBar shows the progress of the task with its status as overlay.
+// An unknown progress is shown as a bar that keeps filling up.
+func progressBar(task *Task, size imgui.Vec2) {
+	fraction, status := task.Progress()
+	if fraction < 0 {
+		fraction = float32(math.Mod(imgui.Time(), 1))
+	} else if status == "" {
+		status = fmt.Sprintf("%.0f%%", fraction*100)
+	}
+	imgui.ProgressBarV(fraction, size, status)
+}
@@ -0,0 +1,86 @@
+package tasks
+
+import (
+	"context"
+	"sync"
+)
+
+// Func is the operation of a task. It runs on a goroutine of its own and must return soon after
+// the context is canceled. It reports its progress with the methods of the task.
+type Func func(ctx context.Context, task *Task) error
+
+// Task is the handle of an operation started by a Manager. Its methods may be called from any goroutine.
+type Task struct {
+	id     int32
+	name   string
+	cancel context.CancelFunc
+
+	mutex    sync.Mutex
+	progress float32
+	status   string
+	finished bool
+	err      error
+}
+
+func newTask(id int32, name string, cancel context.CancelFunc) *Task {
+	return &Task{id: id, name: name, cancel: cancel, progress: -1}
+}
+
+// Name returns the name the task was started with.
+func (task *Task) Name() string {
+	return task.name
+}
+
+// SetProgress sets the completed fraction of the task, between 0 and 1.
+// A negative fraction tells that the progress is unknown, which is the initial state.
+func (task *Task) SetProgress(fraction float32) {
+	if fraction > 1 {
+		fraction = 1
+	}
+	task.mutex.Lock()
+	defer task.mutex.Unlock()
+	task.progress = fraction
+}
+
+// SetStatus sets a short text that describes the current step of the task.
+func (task *Task) SetStatus(status string) {
+	task.mutex.Lock()
+	defer task.mutex.Unlock()
+	task.status = status
+}
+
+// Progress returns the completed fraction, negative if unknown, and the status text of the task.
+func (task *Task) Progress() (float32, string) {
+	task.mutex.Lock()
+	defer task.mutex.Unlock()
+	return task.progress, task.status
+}
+
+// Cancel cancels the context of the task. The task is finished once its function returned.
+func (task *Task) Cancel() {
+	task.cancel()
+}
+
+// Finished returns true once the function of the task returned.
+func (task *Task) Finished() bool {
+	task.mutex.Lock()
+	defer task.mutex.Unlock()
+	return task.finished
+}
+
+// Err returns the error the function of the task returned, or nil while the task is running.
+func (task *Task) Err() error {
+	task.mutex.Lock()
+	defer task.mutex.Unlock()
+	return task.err
+}
+
+func (task *Task) finish(err error) {
+	task.mutex.Lock()
+	defer task.mutex.Unlock()
+	task.finished = true
+	task.err = err
+	if err == nil {
+		task.progress = 1
+	}
+}
@@ -0,0 +1,5 @@
+// Package tasks runs long operations, such as file scans or exports, on goroutines of their own,
+// so that they do not block the frame loop. Tasks report their progress through a handle,
+// which the package shows in a status bar and a window with progress bars and cancel buttons.
+// Results of finished tasks are handed back to the thread of the frame loop with a dispatcher.
+package tasks
@@ -0,0 +1,14 @@
+package tasks
+
+// StringError describes a basic error with static information.
+type StringError string
+
+// Error returns the string itself.
+func (err StringError) Error() string {
+	return string(err)
+}
+
+const (
+	// ErrClosed is used in case a task is started after the manager was closed.
+	ErrClosed = StringError("task manager closed")
+)
@@ -0,0 +1,148 @@
+package textures
+
+import (
+	"io/fs"
+	"time"
+
+	"github.com/AllenDang/cimgui-go"
+)
+
+// Renderer is the part of a renderer that manages textures.
+type Renderer interface {
+	// CreateTexture uploads tightly packed 8-bit RGBA pixels and returns their identifier.
+	CreateTexture(pixels []uint8, width, height int) (imgui.TextureID, error)
+	// UpdateTexture replaces the content of a previously created texture.
+	UpdateTexture(id imgui.TextureID, pixels []uint8, width, height int) error
+	// DeleteTexture releases a previously created texture.
+	DeleteTexture(id imgui.TextureID)
+}
+
+// CacheConfig describes how a Cache loads its textures.
+type CacheConfig struct {
+	// PremultipliedAlpha causes the color channels to be multiplied by alpha before upload.
+	// Only enable this if the textures are drawn with a matching blend function.
+	PremultipliedAlpha bool
+	// ReloadInterval is the minimum time between two checks for modified files.
+	// A value of zero disables hot-reloading.
+	ReloadInterval time.Duration
+}
+
+// Cache shares textures loaded from a file system by their path.
+// A Cache must only be used from the thread that owns the render context.
+type Cache struct {
+	renderer Renderer
+	fsys     fs.FS
+	config   CacheConfig
+
+	textures map[string]*Texture
+	lastPoll time.Time
+}
+
+// NewCache returns a cache that loads images from given file system and uploads them through the renderer.
+func NewCache(renderer Renderer, fsys fs.FS, config CacheConfig) *Cache {
+	return &Cache{
+		renderer: renderer,
+		fsys:     fsys,
+		config:   config,
+		textures: make(map[string]*Texture),
+	}
+}
+
+// Dispose releases all textures, regardless of their references.
+func (cache *Cache) Dispose() {
+	for path, tex := range cache.textures {
+		cache.renderer.DeleteTexture(tex.id)
+		delete(cache.textures, path)
+	}
+}
+
+// Load returns the texture for given path, loading it if necessary.
+// Every successful call must be balanced by a call to Release.
+func (cache *Cache) Load(path string) (*Texture, error) {
+	if tex, cached := cache.textures[path]; cached {
+		tex.references++
+		return tex, nil
+	}
+
+	pixels, width, height, err := decodeFile(cache.fsys, path, cache.config.PremultipliedAlpha)
+	if err != nil {
+		return nil, err
+	}
+	id, err := cache.renderer.CreateTexture(pixels, width, height)
+	if err != nil {
+		return nil, err
+	}
+	tex := &Texture{
+		id:         id,
+		width:      width,
+		height:     height,
+		path:       path,
+		references: 1,
+		modTime:    cache.modTime(path),
+	}
+	cache.textures[path] = tex
+	return tex, nil
+}
+
+// Release gives up one reference to the texture. The texture is deleted
+// from the renderer once the last reference has been released.
+func (cache *Cache) Release(tex *Texture) {
+	if (tex == nil) || (cache.textures[tex.path] != tex) {
+		return
+	}
+	tex.references--
+	if tex.references > 0 {
+		return
+	}
+	cache.renderer.DeleteTexture(tex.id)
+	delete(cache.textures, tex.path)
+}
+
+// Poll reloads all textures whose files have been modified since they were loaded.
+// It is meant to be called once per frame; the actual check is throttled by the reload interval.
+// It returns the number of textures it tried to reload, which is zero for most calls.
+// Files that fail to reload keep their previous content, the first error is returned.
+func (cache *Cache) Poll() (reloaded int, err error) {
+	now := time.Now()
+	if (cache.config.ReloadInterval <= 0) || (now.Sub(cache.lastPoll) < cache.config.ReloadInterval) {
+		return 0, nil
+	}
+	cache.lastPoll = now
+
+	for path, tex := range cache.textures {
+		modTime := cache.modTime(path)
+		if modTime.IsZero() || !modTime.After(tex.modTime) {
+			continue
+		}
+		tex.modTime = modTime
+		reloaded++
+		if reloadErr := cache.reload(tex); (reloadErr != nil) && (err == nil) {
+			err = reloadErr
+		}
+	}
+	return reloaded, err
+}
+
+func (cache *Cache) reload(tex *Texture) error {
+	pixels, width, height, err := decodeFile(cache.fsys, tex.path, cache.config.PremultipliedAlpha)
+	if err != nil {
+		return err
+	}
+	err = cache.renderer.UpdateTexture(tex.id, pixels, width, height)
+	if err != nil {
+		return err
+	}
+	tex.width = width
+	tex.height = height
+	return nil
+}
+
+// modTime returns the modification time of the file, or the zero time if the
+// file system does not provide one. Embedded files always report the zero time.
+func (cache *Cache) modTime(path string) time.Time {
+	info, err := fs.Stat(cache.fsys, path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
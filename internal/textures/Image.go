@@ -0,0 +1,29 @@
+package textures
+
+import (
+	"github.com/AllenDang/cimgui-go"
+)
+
+// FitSize returns the largest size with the aspect ratio of content that fits into bounds.
+func FitSize(content, bounds imgui.Vec2) imgui.Vec2 {
+	if (content.X <= 0) || (content.Y <= 0) {
+		return imgui.Vec2{}
+	}
+	scale := bounds.X / content.X
+	if heightScale := bounds.Y / content.Y; heightScale < scale {
+		scale = heightScale
+	}
+	if scale < 0 {
+		scale = 0
+	}
+	return imgui.Vec2{X: content.X * scale, Y: content.Y * scale}
+}
+
+// Image displays the texture as large as possible within given size, keeping its aspect ratio.
+// A zero size uses the available content region instead.
+func Image(tex *Texture, size imgui.Vec2) {
+	if (size.X <= 0) && (size.Y <= 0) {
+		size = imgui.ContentRegionAvail()
+	}
+	imgui.Image(tex.ID(), FitSize(tex.Size(), size))
+}
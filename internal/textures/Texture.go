@@ -0,0 +1,33 @@
+package textures
+
+import (
+	"time"
+
+	"github.com/AllenDang/cimgui-go"
+)
+
+// Texture is an image that has been uploaded to a renderer.
+type Texture struct {
+	id     imgui.TextureID
+	width  int
+	height int
+
+	path       string
+	references int
+	modTime    time.Time
+}
+
+// ID returns the identifier to be used with imgui.Image and similar functions.
+func (tex *Texture) ID() imgui.TextureID {
+	return tex.id
+}
+
+// Size returns the dimension of the texture in pixels.
+func (tex *Texture) Size() imgui.Vec2 {
+	return imgui.Vec2{X: float32(tex.width), Y: float32(tex.height)}
+}
+
+// Path returns the path the texture was loaded from.
+func (tex *Texture) Path() string {
+	return tex.path
+}
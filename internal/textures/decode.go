@@ -0,0 +1,47 @@
+package textures
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif"  // register GIF decoder
+	_ "image/jpeg" // register JPEG decoder
+	_ "image/png"  // register PNG decoder
+	"io/fs"
+
+	_ "golang.org/x/image/bmp" // register BMP decoder
+)
+
+// decodeFile reads the named file and returns its content as tightly packed 8-bit RGBA pixels.
+// With premultiplied set, the color channels are multiplied by the alpha channel.
+func decodeFile(fsys fs.FS, path string, premultiplied bool) (pixels []uint8, width, height int, err error) {
+	file, err := fsys.Open(path)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	defer func() { _ = file.Close() }()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	pixels, width, height = toRGBA(img, premultiplied)
+	return pixels, width, height, nil
+}
+
+func toRGBA(img image.Image, premultiplied bool) (pixels []uint8, width, height int) {
+	bounds := img.Bounds()
+	target := image.Rect(0, 0, bounds.Dx(), bounds.Dy())
+	// image.RGBA stores premultiplied colors, image.NRGBA stores straight colors.
+	// Both share the same memory layout, so draw.Draw performs the conversion.
+	var dst draw.Image
+	if premultiplied {
+		rgba := image.NewRGBA(target)
+		pixels, dst = rgba.Pix, rgba
+	} else {
+		nrgba := image.NewNRGBA(target)
+		pixels, dst = nrgba.Pix, nrgba
+	}
+	draw.Draw(dst, target, img, bounds.Min, draw.Src)
+	return pixels, target.Dx(), target.Dy()
+}
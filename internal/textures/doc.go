@@ -0,0 +1,6 @@
+// Package textures loads images and keeps them as textures of a renderer.
+// Images are decoded from any io/fs.FS, such as the embedded assets or a
+// directory on disk, and shared by path with reference counting.
+// Textures loaded from a file system that reports modification times are
+// reloaded when the underlying file changes.
+package textures